@@ -35,9 +35,10 @@ func (p *Program) TokenLiteral() string {
 }
 
 type VarStatement struct {
-	Token token.Token // token.VAR
-	Name  *Identifier
-	Value Expression
+	Token   token.Token // token.VAR
+	Name    *Identifier
+	Pattern *ArrayPattern // set instead of Name for var [a, b] = ...
+	Value   Expression
 }
 
 func (vs *VarStatement) statementNode() {
@@ -105,7 +106,11 @@ func (vs *VarStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(vs.TokenLiteral() + " ")
-	out.WriteString(vs.Name.String())
+	if vs.Pattern != nil {
+		out.WriteString(vs.Pattern.String())
+	} else {
+		out.WriteString(vs.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if vs.Value != nil {
@@ -140,6 +145,15 @@ func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -188,6 +202,28 @@ func (oe *InflixExpression) String() string {
 	return out.String()
 }
 
+type AssignExpression struct {
+	Token  token.Token // token.ASSIGN
+	Target Expression  // *Identifier, *IndexExpression or *MemberExpression
+	Value  Expression
+}
+
+func (ae *AssignExpression) expressionNode() {}
+
+func (ae *AssignExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" = ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+	return out.String()
+}
+
 type Boolean struct {
 	Token token.Token
 	Value bool
@@ -201,6 +237,18 @@ func (b *Boolean) String() string {
 	return b.Token.Literal
 }
 
+type NullLiteral struct {
+	Token token.Token
+}
+
+func (nl *NullLiteral) expressionNode() {}
+func (nl *NullLiteral) TokenLiteral() string {
+	return nl.Token.Literal
+}
+func (nl *NullLiteral) String() string {
+	return nl.Token.Literal
+}
+
 type IfExpression struct {
 	Token       token.Token
 	Condition   Expression
@@ -216,18 +264,38 @@ func (ie *IfExpression) TokenLiteral() string {
 
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
-	out.WriteString("if")
+	out.WriteString("if (")
 	out.WriteString(ie.Condition.String())
-	out.WriteString(" ")
-	out.WriteString(ie.Condition.String())
-
-	if ie.Alternative != nil {
-		out.WriteString("else ")
+	out.WriteString(") { ")
+	out.WriteString(ie.Consequence.String())
+	out.WriteString(" }")
+
+	if elseIf := ie.ElseIf(); elseIf != nil {
+		out.WriteString(" else ")
+		out.WriteString(elseIf.String())
+	} else if ie.Alternative != nil {
+		out.WriteString(" else { ")
 		out.WriteString(ie.Alternative.String())
+		out.WriteString(" }")
 	}
 	return out.String()
 }
 
+// ElseIf returns the if expression of an `else if` branch. The parser
+// stores it as the only statement of an Alternative block that starts at
+// the if token. ElseIf returns nil for a plain else block.
+func (ie *IfExpression) ElseIf() *IfExpression {
+	if ie.Alternative == nil || ie.Alternative.Token.Type != token.IF || len(ie.Alternative.Statements) != 1 {
+		return nil
+	}
+	stmt, ok := ie.Alternative.Statements[0].(*ExpressionStatement)
+	if !ok {
+		return nil
+	}
+	elseIf, _ := stmt.Expression.(*IfExpression)
+	return elseIf
+}
+
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
@@ -249,6 +317,8 @@ func (bs *BlockStatement) String() string {
 type FunctionLiteral struct {
 	Token      token.Token
 	Parameters []*Identifier
+	Defaults   map[string]Expression // default values by parameter name
+	Rest       *Identifier           // optional ...rest parameter
 	Body       *BlockStatement
 }
 
@@ -259,21 +329,42 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
-	params := []string{}
-
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
+	if fl.Token.Type == token.ARROW {
+		out.WriteString("(")
+		out.WriteString(FormatParameters(fl.Parameters, fl.Defaults, fl.Rest))
+		out.WriteString(") => ")
+		out.WriteString(fl.Body.String())
+		return out.String()
 	}
 
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(FormatParameters(fl.Parameters, fl.Defaults, fl.Rest))
 	out.WriteString(") ")
 	out.WriteString(fl.Body.String())
 
 	return out.String()
 }
 
+// FormatParameters renders a parameter list such as `a, b = 2, ...rest`.
+func FormatParameters(parameters []*Identifier, defaults map[string]Expression, rest *Identifier) string {
+	params := []string{}
+
+	for _, p := range parameters {
+		if def, ok := defaults[p.Value]; ok {
+			params = append(params, p.String()+" = "+def.String())
+			continue
+		}
+		params = append(params, p.String())
+	}
+
+	if rest != nil {
+		params = append(params, "..."+rest.String())
+	}
+
+	return strings.Join(params, ", ")
+}
+
 type CallExpression struct {
 	Token     token.Token
 	Function  Expression
@@ -318,6 +409,35 @@ func (sl *StringLiteral) String() string {
 	return sl.Token.Literal
 }
 
+type InterpolatedString struct {
+	Token token.Token // token.INTERP_HEAD
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode() {}
+
+func (is *InterpolatedString) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(`"`)
+	for _, part := range is.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			out.WriteString(text.Value)
+			continue
+		}
+		out.WriteString("${")
+		out.WriteString(part.String())
+		out.WriteString("}")
+	}
+	out.WriteString(`"`)
+
+	return out.String()
+}
+
 type ArrayLiteral struct {
 	Token    token.Token
 	Elements []Expression
@@ -368,3 +488,427 @@ func (ie *IndexExpression) String() string {
 
 	return out.String()
 }
+
+// SliceExpression is left[start:end]. Start and End are nil when left out.
+type SliceExpression struct {
+	Token token.Token
+	Left  Expression
+	Start Expression
+	End   Expression
+}
+
+func (se *SliceExpression) expressionNode() {}
+
+func (se *SliceExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
+type MemberExpression struct {
+	Token    token.Token
+	Object   Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode() {}
+
+func (me *MemberExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+func (me *MemberExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(me.Object.String())
+	out.WriteString(".")
+	out.WriteString(me.Property.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+type HashLiteral struct {
+	Token token.Token
+	Keys  []Expression // keys in source order
+	Pairs map[Expression]Expression
+}
+
+func (hl *HashLiteral) expressionNode() {}
+
+func (hl *HashLiteral) TokenLiteral() string {
+	return hl.Token.Literal
+}
+
+func (hl *HashLiteral) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+
+	for _, key := range hl.Keys {
+		pairs = append(pairs, key.String()+": "+hl.Pairs[key].String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode() {}
+
+func (ws *WhileStatement) TokenLiteral() string {
+	return ws.Token.Literal
+}
+
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ws.Body.String())
+
+	return out.String()
+}
+
+type ForStatement struct {
+	Token    token.Token
+	Index    *Identifier // optional, the index or key of each item
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode() {}
+
+func (fs *ForStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fs.Index != nil {
+		out.WriteString(fs.Index.String() + ", ")
+	}
+	out.WriteString(fs.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (bs *BreakStatement) statementNode() {}
+
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs *BreakStatement) String() string {
+	return bs.Token.Literal + ";"
+}
+
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func (cs *ContinueStatement) statementNode() {}
+
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+func (cs *ContinueStatement) String() string {
+	return cs.Token.Literal + ";"
+}
+
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode() {}
+
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+func (ts *ThrowStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
+
+// TryExpression runs Block, handing any error to Handler with the error
+// bound to Param. Finally, when present, always runs last. At least one
+// of Handler and Finally is set.
+type TryExpression struct {
+	Token   token.Token
+	Block   *BlockStatement
+	Param   *Identifier
+	Handler *BlockStatement
+	Finally *BlockStatement
+}
+
+func (te *TryExpression) expressionNode() {}
+
+func (te *TryExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try { ")
+	out.WriteString(te.Block.String())
+	out.WriteString(" }")
+
+	if te.Handler != nil {
+		out.WriteString(" catch (")
+		out.WriteString(te.Param.String())
+		out.WriteString(") { ")
+		out.WriteString(te.Handler.String())
+		out.WriteString(" }")
+	}
+
+	if te.Finally != nil {
+		out.WriteString(" finally { ")
+		out.WriteString(te.Finally.String())
+		out.WriteString(" }")
+	}
+	return out.String()
+}
+
+type MatchExpression struct {
+	Token   token.Token // token.MATCH
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (me *MatchExpression) expressionNode() {}
+
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// MatchArm is a single `pattern if guard => body` arm of a match.
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression // optional
+	Body    Expression
+}
+
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
+// ArrayPattern matches arrays element by element. Elements are patterns
+// themselves, Rest collects whatever is left over.
+type ArrayPattern struct {
+	Token    token.Token // token.LBRACKET
+	Elements []Expression
+	Rest     *Identifier // optional
+}
+
+func (ap *ArrayPattern) expressionNode() {}
+
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// Names returns the names the pattern binds, leaving out `_`.
+func (ap *ArrayPattern) Names() []string {
+	names := []string{}
+	for _, element := range ap.Elements {
+		switch element := element.(type) {
+		case *Identifier:
+			if element.Value != "_" {
+				names = append(names, element.Value)
+			}
+		case *ArrayPattern:
+			names = append(names, element.Names()...)
+		}
+	}
+	if ap.Rest != nil {
+		names = append(names, ap.Rest.Value)
+	}
+	return names
+}
+
+// StructStatement declares a struct type. Fields are filled in order by the
+// arguments of a constructor call, falling back to their defaults.
+type StructStatement struct {
+	Token    token.Token
+	Name     *Identifier
+	Fields   []*Identifier
+	Defaults map[string]Expression
+	Methods  []*StructMethod
+}
+
+func (ss *StructStatement) statementNode() {}
+
+func (ss *StructStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	members := []string{}
+	if len(ss.Fields) > 0 {
+		members = append(members, FormatParameters(ss.Fields, ss.Defaults, nil))
+	}
+	for _, m := range ss.Methods {
+		members = append(members, m.String())
+	}
+
+	out.WriteString(ss.TokenLiteral() + " ")
+	out.WriteString(ss.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(members, "; "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type StructMethod struct {
+	Name     *Identifier
+	Function *FunctionLiteral
+}
+
+func (sm *StructMethod) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(sm.Function.TokenLiteral() + " ")
+	out.WriteString(sm.Name.String())
+	out.WriteString("(")
+	out.WriteString(FormatParameters(sm.Function.Parameters, sm.Function.Defaults, sm.Function.Rest))
+	out.WriteString(") ")
+	out.WriteString(sm.Function.Body.String())
+
+	return out.String()
+}
+
+// ImportStatement loads the module at Path and binds it to Alias.
+type ImportStatement struct {
+	Token token.Token
+	Path  string
+	Alias *Identifier
+}
+
+func (is *ImportStatement) statementNode() {}
+
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+func (is *ImportStatement) String() string {
+	return is.TokenLiteral() + " \"" + is.Path + "\" as " + is.Alias.String() + ";"
+}
+
+// ExportStatement marks the bindings of a top level var, const or struct
+// declaration as visible to modules importing the file.
+type ExportStatement struct {
+	Token     token.Token
+	Statement Statement
+}
+
+func (es *ExportStatement) statementNode() {}
+
+func (es *ExportStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
+
+// Names returns the names bound by the exported declaration.
+func (es *ExportStatement) Names() []string {
+	switch decl := es.Statement.(type) {
+	case *VarStatement:
+		if decl.Pattern != nil {
+			return decl.Pattern.Names()
+		}
+		return []string{decl.Name.Value}
+	case *StructStatement:
+		return []string{decl.Name.Value}
+	}
+	return nil
+}
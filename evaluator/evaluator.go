@@ -2,16 +2,21 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/nazeemnato/sloth/ast"
 	"github.com/nazeemnato/sloth/object"
+	"github.com/nazeemnato/sloth/token"
 )
 
 var (
-	NULL  = &object.Null{}
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
+	NULL     = &object.Null{}
+	TRUE     = &object.Boolean{Value: true}
+	FALSE    = &object.Boolean{Value: false}
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
 )
 
 var builtins = map[string]*object.Builtin{
@@ -22,9 +27,11 @@ var builtins = map[string]*object.Builtin{
 			}
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Hash:
+				return &object.Integer{Value: int64(len(arg.Pairs))}
 			default:
 				return newError("argument to `len` not supported, got %s", args[0].Type())
 			}
@@ -42,6 +49,39 @@ var builtins = map[string]*object.Builtin{
 			return &object.String{Value: strings.Trim(result, " ")}
 		},
 	},
+	"range": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 3 {
+				return newError("wrong number of arguments")
+			}
+
+			bounds := []int64{}
+			for _, arg := range args {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return newError("argument to `range` not supported, got %s", arg.Type())
+				}
+				bounds = append(bounds, integer.Value)
+			}
+
+			start, end, step := int64(0), bounds[0], int64(1)
+			if len(bounds) > 1 {
+				start, end = bounds[0], bounds[1]
+			}
+			if len(bounds) > 2 {
+				step = bounds[2]
+			}
+			if step == 0 {
+				return newError("`range` step must not be zero")
+			}
+
+			elements := []object.Object{}
+			for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+				elements = append(elements, &object.Integer{Value: i})
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
 	"print": {
 		Fn: func(args ...object.Object) object.Object {
 			var result string
@@ -62,8 +102,12 @@ func Eval(node ast.Node, env *object.Enviroment) object.Object {
 		return Eval(node.Expression, env)
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 	case *ast.Boolean:
 		return nativeBooltoBooleanObject(node.Value)
+	case *ast.NullLiteral:
+		return NULL
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
@@ -71,6 +115,9 @@ func Eval(node ast.Node, env *object.Enviroment) object.Object {
 		}
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InflixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -84,7 +131,24 @@ func Eval(node ast.Node, env *object.Enviroment) object.Object {
 		return evalBlockStatement(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.BreakStatement:
+		return BREAK
+	case *ast.ContinueStatement:
+		return CONTINUE
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return &object.Error{Message: val.Inspect(), Value: val}
 	case *ast.ReturnStatement:
+		if node.ReturnValue == nil {
+			return &object.ReturnValue{Value: NULL}
+		}
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
 			return val
@@ -95,13 +159,24 @@ func Eval(node ast.Node, env *object.Enviroment) object.Object {
 		if isError(val) {
 			return val
 		}
-		env.Set(node.Name.Value, val)
+		bind := env.Set
+		if node.Token.Type == token.CONST {
+			bind = func(name string, val object.Object) object.Object {
+				return env.SetConst(name, val, node.Token.Line)
+			}
+		}
+		if node.Pattern != nil {
+			return destructure(node.Pattern, val, bind)
+		}
+		if res := bind(node.Name.Value, val); isError(res) {
+			return res
+		}
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return &object.Function{Parameters: params, Defaults: node.Defaults, Rest: node.Rest, Env: env, Body: body}
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
 		if isError(function) {
@@ -114,6 +189,8 @@ func Eval(node ast.Node, env *object.Enviroment) object.Object {
 		return applyFunction(function, args)
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
@@ -131,6 +208,28 @@ func Eval(node ast.Node, env *object.Enviroment) object.Object {
 		}
 
 		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+	case *ast.MemberExpression:
+		obj := Eval(node.Object, env)
+		if isError(obj) {
+			return obj
+		}
+		return evalMemberExpression(obj, node.Property.Value)
+	case *ast.StructStatement:
+		return evalStructStatement(node, env)
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.ExportStatement:
+		return Eval(node.Statement, env)
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
 	}
 
 	return nil
@@ -145,6 +244,8 @@ func evalProgram(program *ast.Program, env *object.Enviroment) object.Object {
 			return result.Value
 		case *object.Error:
 			return result
+		case *object.Break, *object.Continue:
+			return newError("%s outside loop", result.Inspect())
 		}
 	}
 	return result
@@ -182,30 +283,68 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 func evalMinusOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
 		return newError("unknown operator: -%s", right.Type())
 	}
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
+}
+
+// evalLogicalExpression evaluates && and ||. The right side is only
+// evaluated when the left side does not decide the result already.
+func evalLogicalExpression(node *ast.InflixExpression, env *object.Enviroment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	if node.Operator == "&&" && !isTruthy(left) {
+		return FALSE
+	}
+	if node.Operator == "||" && isTruthy(left) {
+		return TRUE
+	}
+
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+	return nativeBooltoBooleanObject(isTruthy(right))
 }
 
 func evalInfExpression(operator string, left, right object.Object) object.Object {
 	switch {
+	case (left == NULL || right == NULL) && (operator == "==" || operator == "!="):
+		return evalNullComparison(operator, left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalInterInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		return evalFloatInfixExpression(operator, left, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInflixExpression(operator, left, right)
 	case operator == "==":
 		return nativeBooltoBooleanObject(left == right)
 	case operator == "!=":
 		return nativeBooltoBooleanObject(left != right)
 	case left.Type() != right.Type():
 		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
-	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
-		return evalStringInflixExpression(operator, left, right)
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+// evalNullComparison compares against null, which only equals itself.
+func evalNullComparison(operator string, left, right object.Object) object.Object {
+	equal := left == NULL && right == NULL
+	if operator == "!=" {
+		return nativeBooltoBooleanObject(!equal)
+	}
+	return nativeBooltoBooleanObject(equal)
+}
+
 func evalInterInfixExpression(operator string, left, right object.Object) object.Object {
 	leftValue := left.(*object.Integer).Value
 	rightValue := right.(*object.Integer).Value
@@ -218,11 +357,71 @@ func evalInterInfixExpression(operator string, left, right object.Object) object
 	case "*":
 		return &object.Integer{Value: leftValue * rightValue}
 	case "/":
+		if rightValue == 0 {
+			return newError("division by zero")
+		}
 		return &object.Integer{Value: leftValue / rightValue}
+	case "%":
+		if rightValue == 0 {
+			return newError("modulo by zero")
+		}
+		return &object.Integer{Value: leftValue % rightValue}
+	case "**":
+		if rightValue < 0 {
+			return newError("negative exponent: %d", rightValue)
+		}
+		return &object.Integer{Value: intPow(leftValue, rightValue)}
+	case ">":
+		return nativeBooltoBooleanObject(leftValue > rightValue)
+	case "<":
+		return nativeBooltoBooleanObject(leftValue < rightValue)
+	case ">=":
+		return nativeBooltoBooleanObject(leftValue >= rightValue)
+	case "<=":
+		return nativeBooltoBooleanObject(leftValue <= rightValue)
+	case "==":
+		return nativeBooltoBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBooltoBooleanObject(leftValue != rightValue)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+}
+
+// evalFloatInfixExpression handles float operands and mixed int/float
+// operands, promoting integers to floats.
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue := toFloat(left)
+	rightValue := toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftValue + rightValue}
+	case "-":
+		return &object.Float{Value: leftValue - rightValue}
+	case "*":
+		return &object.Float{Value: leftValue * rightValue}
+	case "/":
+		if rightValue == 0 {
+			return newError("division by zero")
+		}
+		return &object.Float{Value: leftValue / rightValue}
+	case "%":
+		if rightValue == 0 {
+			return newError("modulo by zero")
+		}
+		return &object.Float{Value: math.Mod(leftValue, rightValue)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftValue, rightValue)}
 	case ">":
 		return nativeBooltoBooleanObject(leftValue > rightValue)
 	case "<":
 		return nativeBooltoBooleanObject(leftValue < rightValue)
+	case ">=":
+		return nativeBooltoBooleanObject(leftValue >= rightValue)
+	case "<=":
+		return nativeBooltoBooleanObject(leftValue <= rightValue)
 	case "==":
 		return nativeBooltoBooleanObject(leftValue == rightValue)
 	case "!=":
@@ -230,23 +429,152 @@ func evalInterInfixExpression(operator string, left, right object.Object) object
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
+}
+
+// intPow raises base to a non-negative exponent by repeated squaring.
+func intPow(base, exponent int64) int64 {
+	result := int64(1)
+	for exponent > 0 {
+		if exponent%2 == 1 {
+			result *= base
+		}
+		base *= base
+		exponent /= 2
+	}
+	return result
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.FLOAT_OBJ
+}
 
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.Float:
+		return obj.Value
+	default:
+		return 0
+	}
 }
 
 func evalIfExpression(ie *ast.IfExpression, env *object.Enviroment) object.Object {
-	condition := Eval(ie.Condition, env)
-	if isError(condition) {
-		return condition
-	}
-	if isTruthy(condition) {
-		return Eval(ie.Consequence, env)
-	} else if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
-	} else {
+	// walk else if chains in a loop rather than recursing through blocks
+	for {
+		condition := Eval(ie.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if isTruthy(condition) {
+			return Eval(ie.Consequence, env)
+		}
+
+		if elseIf := ie.ElseIf(); elseIf != nil {
+			ie = elseIf
+			continue
+		}
+
+		if ie.Alternative != nil {
+			return Eval(ie.Alternative, env)
+		}
 		return NULL
 	}
 }
 
+func evalWhileStatement(ws *ast.WhileStatement, env *object.Enviroment) object.Object {
+	for {
+		condition := Eval(ws.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return NULL
+		}
+
+		if result, stop := evalLoopBody(ws.Body, env); stop {
+			return result
+		}
+	}
+}
+
+func evalForStatement(fs *ast.ForStatement, env *object.Enviroment) object.Object {
+	iterable := Eval(fs.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	var indexes, values []object.Object
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		values = iterable.Elements
+		for i := range values {
+			indexes = append(indexes, &object.Integer{Value: int64(i)})
+		}
+	case *object.String:
+		i := 0
+		for _, ch := range iterable.Value {
+			indexes = append(indexes, &object.Integer{Value: int64(i)})
+			values = append(values, &object.String{Value: string(ch)})
+			i++
+		}
+	case *object.Hash:
+		for _, key := range iterable.Keys {
+			pair := iterable.Pairs[key]
+			if fs.Index == nil {
+				values = append(values, pair.Key)
+			} else {
+				indexes = append(indexes, pair.Key)
+				values = append(values, pair.Value)
+			}
+		}
+	default:
+		return newError("cannot iterate over %s", iterable.Type())
+	}
+
+	for i, value := range values {
+		// every iteration gets its own scope so closures capture the
+		// current item instead of the last one
+		loopEnv := object.NewEnclosedEnviroment(env)
+		if fs.Index != nil {
+			loopEnv.Set(fs.Index.Value, indexes[i])
+		}
+		loopEnv.Set(fs.Value.Value, value)
+
+		if result, stop := evalLoopBody(fs.Body, loopEnv); stop {
+			return result
+		}
+	}
+
+	return NULL
+}
+
+// evalLoopBody runs one iteration of a loop body and reports whether the
+// loop has to stop, along with the value the loop should produce.
+func evalLoopBody(body *ast.BlockStatement, env *object.Enviroment) (object.Object, bool) {
+	result := Eval(body, env)
+
+	switch result {
+	case BREAK:
+		return NULL, true
+	case CONTINUE:
+		return nil, false
+	}
+
+	if result != nil {
+		rt := result.Type()
+		if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+			return result, true
+		}
+	}
+	return nil, false
+}
+
+func isLoopControl(obj object.Object) bool {
+	return obj == BREAK || obj == CONTINUE
+}
+
 func isTruthy(obj object.Object) bool {
 	switch obj {
 	case NULL:
@@ -260,14 +588,18 @@ func isTruthy(obj object.Object) bool {
 	}
 }
 
+// evalBlockStatement runs block in a scope of its own: declarations stay
+// inside the block, while assignments still reach outer bindings.
 func evalBlockStatement(block *ast.BlockStatement, env *object.Enviroment) object.Object {
 	var result object.Object
 
+	blockEnv := object.NewEnclosedEnviroment(env)
+
 	for _, statement := range block.Statements {
-		result = Eval(statement, env)
+		result = Eval(statement, blockEnv)
 		if result != nil {
 			rt := result.Type()
-			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ || rt == object.BREAK_OBJ || rt == object.CONTINUE_OBJ {
 				return result
 			}
 
@@ -313,24 +645,96 @@ func evalExpressions(exps []ast.Expression, env *object.Enviroment) []object.Obj
 func applyFunction(fun object.Object, args []object.Object) object.Object {
 	switch fun := fun.(type) {
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fun, args)
+		extendedEnv, err := extendFunctionEnv(fun, args)
+		if err != nil {
+			return err
+		}
 		evaluated := Eval(fun.Body, extendedEnv)
-		return unWrapReturnValue(evaluated)
+		if isLoopControl(evaluated) {
+			return newError("%s outside loop", evaluated.Inspect())
+		}
+		// an empty body, or one ending in a declaration, gives back null
+		if result := unWrapReturnValue(evaluated); result != nil {
+			return result
+		}
+		return NULL
 	case *object.Builtin:
 		return fun.Fn(args...)
+	case *object.Struct:
+		return newInstance(fun, args)
 	default:
 		return newError("not a function: %s", fun.Type())
 	}
 }
 
-func extendFunctionEnv(fun *object.Function, args []object.Object) *object.Enviroment {
+// extendFunctionEnv binds the arguments of a call, and the receiver as
+// self when fun is a method.
+func extendFunctionEnv(fun *object.Function, args []object.Object) (*object.Enviroment, *object.Error) {
 	env := object.NewEnclosedEnviroment(fun.Env)
 
-	for paramIdx, param := range fun.Parameters {
-		env.Set(param.Value, args[paramIdx])
+	if fun.Self != nil {
+		env.Set("self", fun.Self)
+	}
+
+	if err := bindArguments(env, fun.Parameters, fun.Defaults, fun.Rest, args); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// bindArguments binds args to params in env. Missing arguments take their
+// default value, evaluated in env so it can refer to earlier parameters,
+// and extra arguments are collected into the rest parameter.
+func bindArguments(env *object.Enviroment, params []*ast.Identifier, defaults map[string]ast.Expression, rest *ast.Identifier, args []object.Object) *object.Error {
+	if err := checkArity(params, defaults, rest, len(args)); err != nil {
+		return err
+	}
+
+	for paramIdx, param := range params {
+		if paramIdx < len(args) {
+			env.Set(param.Value, args[paramIdx])
+			continue
+		}
+
+		val := Eval(defaults[param.Value], env)
+		if err, ok := val.(*object.Error); ok {
+			return err
+		}
+		env.Set(param.Value, val)
+	}
+
+	if rest != nil {
+		extra := []object.Object{}
+		if len(args) > len(params) {
+			extra = append(extra, args[len(params):]...)
+		}
+		env.Set(rest.Value, &object.Array{Elements: extra})
+	}
+
+	return nil
+}
+
+func checkArity(params []*ast.Identifier, defaults map[string]ast.Expression, rest *ast.Identifier, got int) *object.Error {
+	min := 0
+	for i, param := range params {
+		if _, ok := defaults[param.Value]; !ok {
+			min = i + 1
+		}
 	}
+	max := len(params)
 
-	return env
+	switch {
+	case rest != nil && got < min:
+		return newError("wrong number of arguments: want at least %d, got=%d", min, got)
+	case rest != nil:
+		return nil
+	case min == max && got != min:
+		return newError("wrong number of arguments: want=%d, got=%d", min, got)
+	case got < min || got > max:
+		return newError("wrong number of arguments: want %d to %d, got=%d", min, max, got)
+	}
+	return nil
 }
 
 func unWrapReturnValue(obj object.Object) object.Object {
@@ -341,21 +745,53 @@ func unWrapReturnValue(obj object.Object) object.Object {
 }
 
 func evalStringInflixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
-	}
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
 
-	return &object.String{
-		Value: leftVal + rightVal,
+	switch operator {
+	case "+":
+		return &object.String{
+			Value: leftVal + rightVal,
+		}
+	case "==":
+		return nativeBooltoBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBooltoBooleanObject(leftVal != rightVal)
+	case "<":
+		return nativeBooltoBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBooltoBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBooltoBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBooltoBooleanObject(leftVal >= rightVal)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalInterpolatedString(node *ast.InterpolatedString, env *object.Enviroment) object.Object {
+	var out strings.Builder
+
+	for _, part := range node.Parts {
+		value := Eval(part, env)
+		if isError(value) {
+			return value
+		}
+		out.WriteString(value.Inspect())
 	}
+
+	return &object.String{Value: out.String()}
 }
 
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index)
+	case left.Type() == object.HASH_OBJ:
+		return evalHashIndexExpression(left, index)
 	default:
 		return newError("index operator not supported")
 	}
@@ -364,12 +800,439 @@ func evalIndexExpression(left, index object.Object) object.Object {
 
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arrayObject := array.(*object.Array)
-	idx := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(arrayObject.Elements))
 
-	if idx < 0 || idx > max {
+	if !ok {
 		return NULL
 	}
 
 	return arrayObject.Elements[idx]
 }
+
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	chars := []rune(str.(*object.String).Value)
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(chars))
+
+	if !ok {
+		return NULL
+	}
+
+	return &object.String{Value: string(chars[idx])}
+}
+
+// normalizeIndex turns a negative index, which counts back from the end,
+// into one counting from the start. It reports false when idx is out of
+// range for length items.
+func normalizeIndex(idx int64, length int) (int64, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	return idx, idx >= 0 && idx < int64(length)
+}
+
+func evalSliceExpression(node *ast.SliceExpression, env *object.Enviroment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		length = len([]rune(left.Value))
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+
+	start, err := evalSliceBound(node.Start, env, 0, length)
+	if err != nil {
+		return err
+	}
+	end, err := evalSliceBound(node.End, env, length, length)
+	if err != nil {
+		return err
+	}
+	if end < start {
+		end = start
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		elements := make([]object.Object, end-start)
+		copy(elements, left.Elements[start:end])
+		return &object.Array{Elements: elements}
+	default:
+		return &object.String{Value: string([]rune(left.(*object.String).Value)[start:end])}
+	}
+}
+
+// evalSliceBound evaluates one bound of a slice, clamped to [0, length].
+// A negative bound counts back from the end and a missing one gives def.
+func evalSliceBound(node ast.Expression, env *object.Enviroment, def, length int) (int, object.Object) {
+	if node == nil {
+		return def, nil
+	}
+
+	bound := Eval(node, env)
+	if isError(bound) {
+		return 0, bound
+	}
+
+	integer, ok := bound.(*object.Integer)
+	if !ok {
+		return 0, newError("slice index must be INTEGER, got %s", bound.Type())
+	}
+
+	idx := integer.Value
+	if idx < 0 {
+		idx += int64(length)
+	}
+
+	switch {
+	case idx < 0:
+		return 0, nil
+	case idx > int64(length):
+		return length, nil
+	}
+	return int(idx), nil
+}
+
+func evalHashLiteral(node *ast.HashLiteral, env *object.Enviroment) object.Object {
+	hash := &object.Hash{Pairs: make(map[object.HashKey]object.HashPair)}
+
+	for _, keyNode := range node.Keys {
+		key := Eval(keyNode, env)
+		if isError(key) {
+			return key
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(node.Pairs[keyNode], env)
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
+	}
+
+	return hash
+}
+
+func evalHashIndexExpression(hash, index object.Object) object.Object {
+	hashObject := hash.(*object.Hash)
+
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newError("unusable as hash key: %s", index.Type())
+	}
+
+	pair, ok := hashObject.Pairs[key.HashKey()]
+	if !ok {
+		return NULL
+	}
+
+	return pair.Value
+}
+
+func evalAssignExpression(node *ast.AssignExpression, env *object.Enviroment) object.Object {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		res, ok := env.Assign(target.Value, val)
+		if !ok {
+			return newError("assignment to undeclared identifier: %s", target.Value)
+		}
+		return res
+	case *ast.IndexExpression:
+		left := Eval(target.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(target.Index, env)
+		if isError(index) {
+			return index
+		}
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return evalIndexAssignment(left, index, val)
+	case *ast.MemberExpression:
+		obj := Eval(target.Object, env)
+		if isError(obj) {
+			return obj
+		}
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return evalMemberAssignment(obj, target.Property.Value, val)
+	default:
+		return newError("invalid assignment target: %s", node.Target.String())
+	}
+}
+
+func evalIndexAssignment(left, index, val object.Object) object.Object {
+	switch {
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		arrayObject := left.(*object.Array)
+		given := index.(*object.Integer).Value
+		idx, ok := normalizeIndex(given, len(arrayObject.Elements))
+
+		if !ok {
+			return newError("index out of range: %d (length %d)", given, len(arrayObject.Elements))
+		}
+
+		arrayObject.Elements[idx] = val
+		return val
+	case left.Type() == object.HASH_OBJ:
+		hashObject := left.(*object.Hash)
+
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+
+		hashObject.Set(key.HashKey(), object.HashPair{Key: index, Value: val})
+		return val
+	default:
+		return newError("index assignment not supported: %s", left.Type())
+	}
+}
+
+// destructure binds the names of a var pattern to the items of value using
+// bind. It returns an error when value is not an array of a fitting length.
+func destructure(pattern *ast.ArrayPattern, value object.Object, bind func(string, object.Object) object.Object) object.Object {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return newError("cannot destructure %s into %s", value.Type(), pattern.String())
+	}
+
+	want, got := len(pattern.Elements), len(array.Elements)
+	if pattern.Rest == nil && got != want {
+		return newError("wrong number of values to destructure: want=%d, got=%d", want, got)
+	}
+	if pattern.Rest != nil && got < want {
+		return newError("wrong number of values to destructure: want at least %d, got=%d", want, got)
+	}
+
+	for i, element := range pattern.Elements {
+		switch element := element.(type) {
+		case *ast.Identifier:
+			if element.Value == "_" {
+				continue
+			}
+			if res := bind(element.Value, array.Elements[i]); isError(res) {
+				return res
+			}
+		case *ast.ArrayPattern:
+			if err := destructure(element, array.Elements[i], bind); err != nil {
+				return err
+			}
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, got-want)
+		copy(rest, array.Elements[want:])
+		if res := bind(pattern.Rest.Value, &object.Array{Elements: rest}); isError(res) {
+			return res
+		}
+	}
+
+	return nil
+}
+
+func evalMatchExpression(me *ast.MatchExpression, env *object.Enviroment) object.Object {
+	subject := Eval(me.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range me.Arms {
+		// bindings of an arm are only visible to its guard and body
+		armEnv := object.NewEnclosedEnviroment(env)
+
+		matched, err := matchPattern(arm.Pattern, subject, armEnv)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, armEnv)
+	}
+
+	return NULL
+}
+
+// matchPattern reports whether value fits pattern and binds the names the
+// pattern introduces in env. The returned object is an error raised while
+// evaluating a value pattern, if any.
+func matchPattern(pattern ast.Expression, value object.Object, env *object.Enviroment) (bool, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			env.Set(pattern.Value, value)
+		}
+		return true, nil
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if !ok {
+			return false, nil
+		}
+
+		if len(array.Elements) < len(pattern.Elements) || (pattern.Rest == nil && len(array.Elements) != len(pattern.Elements)) {
+			return false, nil
+		}
+
+		for i, element := range pattern.Elements {
+			matched, err := matchPattern(element, array.Elements[i], env)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+
+		if pattern.Rest != nil {
+			rest := make([]object.Object, len(array.Elements)-len(pattern.Elements))
+			copy(rest, array.Elements[len(pattern.Elements):])
+			env.Set(pattern.Rest.Value, &object.Array{Elements: rest})
+		}
+		return true, nil
+	default:
+		expected := Eval(pattern, env)
+		if isError(expected) {
+			return false, expected
+		}
+		return evalInfExpression("==", expected, value) == TRUE, nil
+	}
+}
+
+// evalTryExpression hands an error from the try block to the catch block,
+// then runs the finally block whatever happened. A finally block that
+// returns, fails or breaks out of a loop wins over the earlier result.
+func evalTryExpression(te *ast.TryExpression, env *object.Enviroment) object.Object {
+	result := Eval(te.Block, env)
+
+	if err, ok := result.(*object.Error); ok && te.Handler != nil {
+		handlerEnv := object.NewEnclosedEnviroment(env)
+		handlerEnv.Set(te.Param.Value, caughtValue(err))
+		result = Eval(te.Handler, handlerEnv)
+	}
+
+	if te.Finally != nil {
+		final := Eval(te.Finally, env)
+		if _, ok := final.(*object.ReturnValue); ok || isError(final) || isLoopControl(final) {
+			return final
+		}
+	}
+
+	return result
+}
+
+// caughtValue is what a catch block sees: the thrown value itself, or a
+// hash holding the message of a runtime error.
+func caughtValue(err *object.Error) object.Object {
+	if err.Value != nil {
+		return err.Value
+	}
+
+	key := &object.String{Value: "message"}
+	hash := &object.Hash{Pairs: make(map[object.HashKey]object.HashPair)}
+	hash.Set(key.HashKey(), object.HashPair{Key: key, Value: &object.String{Value: err.Message}})
+	return hash
+}
+
+func evalStructStatement(node *ast.StructStatement, env *object.Enviroment) object.Object {
+	st := &object.Struct{
+		Name:     node.Name.Value,
+		Fields:   node.Fields,
+		Defaults: node.Defaults,
+		Methods:  make(map[string]*object.Function),
+		Env:      env,
+	}
+
+	for _, method := range node.Methods {
+		st.Methods[method.Name.Value] = Eval(method.Function, env).(*object.Function)
+	}
+
+	if res := env.Set(node.Name.Value, st); isError(res) {
+		return res
+	}
+	return nil
+}
+
+// newInstance builds an instance of st, filling its fields in order from
+// args the way a function call binds its parameters.
+func newInstance(st *object.Struct, args []object.Object) object.Object {
+	env := object.NewEnclosedEnviroment(st.Env)
+	if err := bindArguments(env, st.Fields, st.Defaults, nil, args); err != nil {
+		return err
+	}
+
+	instance := &object.Instance{Struct: st, Fields: make(map[string]object.Object)}
+	for _, field := range st.Fields {
+		instance.Fields[field.Value], _ = env.Get(field.Value)
+	}
+	return instance
+}
+
+// evalMemberExpression looks up an export of a module, a field or method
+// of a struct instance, or a method of a built-in type.
+func evalMemberExpression(obj object.Object, name string) object.Object {
+	if module, ok := obj.(*object.Module); ok {
+		if val, ok := module.Export(name); ok {
+			return val
+		}
+		return newError("module %s has no export %s", module.Path, name)
+	}
+
+	instance, ok := obj.(*object.Instance)
+	if !ok {
+		if method, ok := methods[obj.Type()][name]; ok {
+			return bindMethod(method, obj)
+		}
+		return newError("unknown member %s on %s", name, obj.Type())
+	}
+
+	if val, ok := instance.Fields[name]; ok {
+		return val
+	}
+	if method, ok := instance.Struct.Method(name, instance); ok {
+		return method
+	}
+	return newError("unknown member %s on %s", name, instance.Struct.Name)
+}
+
+func evalMemberAssignment(obj object.Object, name string, val object.Object) object.Object {
+	instance, ok := obj.(*object.Instance)
+	if !ok {
+		return newError("member assignment not supported: %s", obj.Type())
+	}
+
+	if _, ok := instance.Fields[name]; !ok {
+		return newError("unknown field %s on %s", name, instance.Struct.Name)
+	}
+	instance.Fields[name] = val
+	return val
+}
@@ -1,6 +1,10 @@
 package evaluator
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nazeemnato/sloth/lexer"
@@ -19,14 +23,91 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"10 + 10", 20},
 		{"5 + 5 + 5 + 5 - 10", 10},
 		{"1 + 1", 2},
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"2 ** 10", 1024},
+		{"2 ** 0", 1},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"2 * 3 ** 2", 18},
 	}
 
 	for _, tt := range tests {
-		evaluted := testEval(tt.input)
+		evaluted := testEval(t, tt.input)
 		testIntegerObject(t, evaluted, tt.expected)
 	}
 }
 
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"2.5", 2.5},
+		{"-2.5", -2.5},
+		{"1.5 + 1.5", 3.0},
+		{"1 + 0.5", 1.5},
+		{"0.5 * 4", 2.0},
+		{"10 - 2.5", 7.5},
+		{"7 / 2.0", 3.5},
+		{"7.5 % 2", 1.5},
+		{"2.0 ** -1", 0.5},
+		{"4 ** 0.5", 2.0},
+	}
+
+	for _, tt := range tests {
+		evaluted := testEval(t, tt.input)
+		testFloatObject(t, evaluted, tt.expected)
+	}
+}
+
+func TestEvalMixedNumberComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1.5 < 2", true},
+		{"2 > 1.5", true},
+		{"2 == 2.0", true},
+		{"2.5 != 2.5", false},
+		{"0.1 + 0.2 > 0.3", true},
+		{"1 <= 1", true},
+		{"2 <= 1", false},
+		{"1 >= 1", true},
+		{"1 >= 2", false},
+		{"1.5 >= 1", true},
+		{"1 <= 0.5", false},
+		{`"a" == "a"`, true},
+		{`"a" != "a"`, false},
+		{`"abc" < "abd"`, true},
+		{`"b" >= "a"`, true},
+		{`"a" <= "a"`, true},
+	}
+
+	for _, tt := range tests {
+		evaluted := testEval(t, tt.input)
+		testBooleanObject(t, evaluted, tt.expected)
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.25", "1.25"},
+		{"2.0", "2.0"},
+		{"1 / 2.0", "0.5"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong Inspect for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
 func TestEvalBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -37,7 +118,7 @@ func TestEvalBooleanExpression(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		evaluted := testEval(tt.input)
+		evaluted := testEval(t, tt.input)
 		testBooleanObject(t, evaluted, tt.expected)
 	}
 }
@@ -57,11 +138,81 @@ func TestBangExpression(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		evaluted := testEval(tt.input)
+		evaluted := testEval(t, tt.input)
+		testBooleanObject(t, evaluted, tt.expected)
+	}
+}
+
+func TestLogicalExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true && true", true},
+		{"true && false", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"5 && 0", true},
+		{`"" || false`, true},
+		{"false && missing", false},
+		{"true || missing", true},
+		{"var calls = 0; var f = fun() { calls = calls + 1; true }; false && f(); calls == 0", true},
+		{"var calls = 0; var f = fun() { calls = calls + 1; true }; true && f(); calls == 1", true},
+	}
+
+	for _, tt := range tests {
+		evaluted := testEval(t, tt.input)
+		testBooleanObject(t, evaluted, tt.expected)
+	}
+}
+
+func TestNullComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"null == null", true},
+		{"null != null", false},
+		{"1 == null", false},
+		{"null != 1", true},
+		{"1.5 == null", false},
+		{`"" == null`, false},
+		{`"null" != null`, true},
+		{"false == null", false},
+		{"[] == null", false},
+		{"{} != null", true},
+		{"fun() {} == null", false},
+		{"fun() {}() == null", true},
+		{"fun() { var x = 1 }() == null", true},
+		{"len == null", false},
+		{"[1][5] == null", true},
+		{`{"a": 1}["b"] == null`, true},
+		{"!null", true},
+	}
+
+	for _, tt := range tests {
+		evaluted := testEval(t, tt.input)
 		testBooleanObject(t, evaluted, tt.expected)
 	}
 }
 
+func TestNullLiteral(t *testing.T) {
+	tests := []string{
+		"null",
+		"var x = null; x",
+		"var f = fun() { return null; 1 }; f()",
+		"var f = fun() { return; 1 }; f()",
+		"var f = fun() { if (true) { return } 1 }; f()",
+		"if (null) { 1 }",
+	}
+
+	for _, input := range tests {
+		testNullObject(t, testEval(t, input))
+	}
+}
+
 func TestIfElseExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -70,10 +221,15 @@ func TestIfElseExpression(t *testing.T) {
 		{"if(true) { 19 }", 19},
 		{"if(false) { 19 }", nil},
 		{"if( 1 < 2) { 1 }", 1},
+		{"if (1 > 2) { 1 } else { 2 }", 2},
+		{"if (1 > 2) { 1 } else if (2 > 1) { 2 } else { 3 }", 2},
+		{"if (1 > 2) { 1 } else if (2 > 3) { 2 } else { 3 }", 3},
+		{"if (1 > 2) { 1 } else if (2 > 3) { 2 }", nil},
+		{"var grade = fun(n) { if (n >= 90) { 1 } else if (n >= 80) { 2 } else if (n >= 70) { 3 } else { 4 } }; grade(75)", 3},
 	}
 
 	for _, tt := range tests {
-		evaluated := testEval(tt.input)
+		evaluated := testEval(t, tt.input)
 		integer, ok := tt.expected.(int)
 		if ok {
 			testIntegerObject(t, evaluated, int64(integer))
@@ -82,6 +238,19 @@ func TestIfElseExpression(t *testing.T) {
 		}
 	}
 }
+func TestLongElseIfChain(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("var n = 4999; ")
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			input.WriteString(" else ")
+		}
+		input.WriteString(fmt.Sprintf("if (n == %d) { %d }", i, i*2))
+	}
+
+	testIntegerObject(t, testEval(t, input.String()), 9998)
+}
+
 func TestReturnExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -101,7 +270,7 @@ func TestReturnExpression(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		evaluted := testEval(tt.input)
+		evaluted := testEval(t, tt.input)
 		testIntegerObject(t, evaluted, tt.expected)
 	}
 }
@@ -112,6 +281,24 @@ func TestErrorHandling(t *testing.T) {
 		expectedMessage string
 	}{
 		{"5 + true", "type mismatch: INTEGER + BOOLEAN"},
+		{"1.5 + true", "type mismatch: FLOAT + BOOLEAN"},
+		{"true && missing", "identifier not found: missing"},
+		{`"value: ${missing}"`, "identifier not found: missing"},
+		{"null + 1", "type mismatch: NULL + INTEGER"},
+		{"null < null", "unknown operator: NULL < NULL"},
+		{"match (missing) { _ => 1 }", "identifier not found: missing"},
+		{"match (1) { 1 if missing => 1 }", "identifier not found: missing"},
+		{"match (1) { 1 => missing }", "identifier not found: missing"},
+		{"5 % 0", "modulo by zero"},
+		{"5 / 0", "division by zero"},
+		{"var f = fun(n) { 10 / n }; f(0)", "division by zero"},
+		{"5.5 % 0", "modulo by zero"},
+		{"5.0 / 0", "division by zero"},
+		{"fun() {}() + 1", "type mismatch: NULL + INTEGER"},
+		{"5 / 0.0", "division by zero"},
+		{"2 ** -1", "negative exponent: -1"},
+		{"true >= false", "unknown operator: BOOLEAN >= BOOLEAN"},
+		{"-\"sloth\"", "unknown operator: -STRING"},
 		{"-true", "unknown operator: -BOOLEAN"},
 		{`if(10 > 1) {
 			if(10 > 1) {
@@ -127,10 +314,14 @@ func TestErrorHandling(t *testing.T) {
 			`"Hello" - "World"`,
 			"unknown operator: STRING - STRING",
 		},
+		{
+			`{"name": "sloth"}[fun(x) { x }];`,
+			"unusable as hash key: FUNCTION",
+		},
 	}
 
 	for _, tt := range tests {
-		evaluted := testEval(tt.input)
+		evaluted := testEval(t, tt.input)
 		errObj, ok := evaluted.(*object.Error)
 
 		if !ok {
@@ -154,14 +345,206 @@ func TestVarStatements(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		testIntegerObject(t, testEval(tt.input), tt.expected)
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"var x = 1; x = 2; x", 2},
+		{"var x = 1; x = x + 41", 42},
+		{"var a = 1; var b = 1; a = b = 5; a + b", 10},
+		{"var x = 1; var set = fun() { x = 10 }; set(); x", 10},
+		{"var counter = fun() { var n = 0; fun() { n = n + 1 } }; var next = counter(); next(); next(); next()", 3},
+		{"var x = 1; var f = fun(x) { x = 5; x }; f(2) + x", 6},
+		{"var xs = [1, 2, 3]; xs[1] = 20; xs[1]", 20},
+		{"var xs = [1, 2, 3]; xs[-1] = 30; xs[2]", 30},
+		{"var xs = [1, 2, 3]; var ys = xs; ys[0] = 9; xs[0]", 9},
+		{"var grid = [[1, 2], [3, 4]]; grid[1][0] = 7; grid[1][0]", 7},
+		{`var h = {"a": 1}; h["b"] = 2; h["a"] + h["b"]`, 3},
+		{`var h = {"a": 1}; h["a"] = 5; len(h) + h["a"]`, 6},
+		{"var sum = 0; for (x in [1, 2, 3]) { sum = sum + x }; sum", 6},
+		{"var fs = [0, 0, 0]; for (i, x in [10, 20, 30]) { fs[i] = fun() { x } }; fs[0]() + fs[2]()", 40},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"const a = 5; a", 5},
+		{"const [a, b] = [1, 2]; a + b", 3},
+		{"const a = 1; var f = fun() { var a = 2; a = 3; a }; f() + a", 4},
+		{"const a = 1; var f = fun(a) { a = 5; a }; f(0)", 5},
+		{"const xs = [1, 2]; xs[0] = 9; xs[0]", 9},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestConstErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"const a = 1; a = 2", "cannot reassign constant a (declared on line 1)"},
+		{"const a = 1;\nvar a = 2", "cannot redeclare constant a (declared on line 1)"},
+		{"var x = 0;\nconst a = 1;\nconst a = 2", "cannot redeclare constant a (declared on line 2)"},
+		{"const a = 1;\nvar f = fun() { a = 2 };\nf()", "cannot reassign constant a (declared on line 1)"},
+		{"const [a, b] = [1, 2]; b = 3", "cannot reassign constant b (declared on line 1)"},
+		{"const a = 1; var [b, a] = [1, 2]", "cannot redeclare constant a (declared on line 1)"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestAssignErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"y = 1", "assignment to undeclared identifier: y"},
+		{"var f = fun() { z = 1 }; f()", "assignment to undeclared identifier: z"},
+		{"var xs = [1, 2]; xs[2] = 3", "index out of range: 2 (length 2)"},
+		{"var xs = [1, 2]; xs[-3] = 3", "index out of range: -3 (length 2)"},
+		{`var s = "abc"; s[0] = "x"`, "index assignment not supported: STRING"},
+		{`var h = {}; h[[1]] = 1`, "unusable as hash key: ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"match (1) { 1 => 10, 2 => 20 }", 10},
+		{"match (2) { 1 => 10, 2 => 20 }", 20},
+		{"match (3) { 1 => 10, 2 => 20 }", nil},
+		{"match (3) { 1 => 10, _ => 0 }", 0},
+		{"match (7) { n => n * 2 }", 14},
+		{`match ("b") { "a" => 1, "b" => 2 }`, 2},
+		{"match (2.5) { 2.5 => 1, _ => 2 }", 1},
+		{"match (-1) { -1 => 1, _ => 2 }", 1},
+		{"match (true) { false => 1, true => 2 }", 2},
+		{"match (null) { 0 => 1, null => 2 }", 2},
+		{"match ([1, 2]) { [a, b] => a + b }", 3},
+		{"match ([1, 2, 3]) { [a, b] => 0, [a, b, c] => c }", 3},
+		{"match ([1, 2, 3]) { [1, x, 3] => x }", 2},
+		{"match ([1, 2, 3]) { [2, x, 3] => x, _ => 0 }", 0},
+		{"match ([1, 2, 3, 4]) { [first, ...rest] => len(rest) }", 3},
+		{"match ([1]) { [first, ...rest] => len(rest) }", 0},
+		{"match ([]) { [first, ...rest] => 1, [] => 2 }", 2},
+		{"match ([1, [2, 3]]) { [a, [b, c]] => a + b + c }", 6},
+		{"match (5) { 5 => 1 }", 1},
+		{"match (5) { n if n > 10 => 1, n if n > 1 => 2, _ => 3 }", 2},
+		{"match ([4, 1]) { [a, b] if a < b => a, [a, b] => b }", 1},
+		{"match (1) { x => x }; match (2) { y => 0 }", 0},
+		{"var x = 1; match (5) { x => x }; x", 1},
+		{"match (1) { 1 => 2, }", 2},
+		{"match (3) { (1 + 2) => 1, _ => 2 }", 1},
+		{"var ok = true; match (3) { n if ok => n }", 3},
+		{"match (fun(g) { g(1) }) { f => f(x => x + 1) }", 2},
+		{"match (3) { n if [1].map(x => x).len() > 0 => n, _ => 0 }", 3},
+		{"match (3) { n if (fun(f) { f(n) })(x => x > 1) => n, _ => 0 }", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestDestructuringVarStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"var [a, b] = [1, 2]; a + b", 3},
+		{"var [a, _, c] = [1, 2, 3]; a + c", 4},
+		{"var [first, ...rest] = [1, 2, 3]; first + len(rest)", 3},
+		{"var [first, ...rest] = [1]; len(rest)", 0},
+		{"var [a, [b, c]] = [1, [2, 3]]; a + b + c", 6},
+		{"var [a, [b, ...more]] = [1, [2, 3, 4]]; more[1]", 4},
+		{"var minmax = fun(x, y) { if (x < y) { [x, y] } else { [y, x] } }; var [lo, hi] = minmax(9, 4); hi - lo", 5},
+		{"var [x, y] = [1, 2]; var [x, y] = [y, x]; x * 10 + y", 21},
+		{"var xs = [1, 2, 3]; var [...all] = xs; all[0] = 9; xs[0]", 1},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"var [a, b] = [1]", "wrong number of values to destructure: want=2, got=1"},
+		{"var [a, b] = [1, 2, 3]", "wrong number of values to destructure: want=2, got=3"},
+		{"var [a, b, ...c] = [1]", "wrong number of values to destructure: want at least 2, got=1"},
+		{"var [a, [b, c]] = [1, [2]]", "wrong number of values to destructure: want=2, got=1"},
+		{"var [a, b] = 5", "cannot destructure INTEGER into [a, b]"},
+		{"var [a, [b]] = [1, 2]", "cannot destructure INTEGER into [b]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
 	}
 }
 
 func TestFunctionObject(t *testing.T) {
 	input := "fun(x) { x + 2; }"
 
-	evaluated := testEval(input)
+	evaluated := testEval(t, input)
 
 	fun, ok := evaluated.(*object.Function)
 
@@ -194,105 +577,729 @@ func TestFunctionApplication(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		testIntegerObject(t, testEval(tt.input), tt.expected)
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
 	}
 }
 
-func TestClosures(t *testing.T) {
-	input := `
-	var add = fun(x) {
-		fun(y) {x + y};
-	};
-	var addTwo = add(2)
-	addTwo(2)
-	`
-	testIntegerObject(t, testEval(input), 4)
-}
-
-func testEval(input string) object.Object {
-	l := lexer.New(input)
-	p := parser.New(l)
-	env := object.NewEnviroment()
-	program := p.ParseProgram()
-	return Eval(program, env)
-}
-
-func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
-	result, ok := obj.(*object.Integer)
-
-	if !ok {
-		t.Errorf("object is not integer got=%T (%+v)", obj, obj)
-		return false
+func TestDefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"var add = fun(a, b = 2) { a + b }; add(1)", 3},
+		{"var add = fun(a, b = 2) { a + b }; add(1, 5)", 6},
+		{"var f = fun(a, b = a * 2) { b }; f(4)", 8},
+		{"var n = 1; var f = fun(a = n) { a }; n = 7; f()", 7},
+		{"var f = fun(a, ...rest) { len(rest) }; f(1)", 0},
+		{"var f = fun(a, ...rest) { rest[1] }; f(1, 2, 3)", 3},
+		{"var f = fun(...all) { len(all) }; f(1, 2, 3, 4)", 4},
+		{"var f = fun(a, b = 10, ...rest) { b + len(rest) }; f(1)", 10},
 	}
 
-	if result.Value != expected {
-		t.Errorf("pbject as wrong value got=%d, want=%d", result.Value, expected)
-		return false
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
 	}
-
-	return true
 }
 
-func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
-	result, ok := obj.(*object.Boolean)
-
-	if !ok {
-		t.Errorf("object is not integer got=%T (%+v)", obj, obj)
-		return false
-	}
-
-	if result.Value != expected {
-		t.Errorf("pbject as wrong value got=%t, want=%t", result.Value, expected)
-		return false
+func TestArityErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"fun(a, b) { a }(1)", "wrong number of arguments: want=2, got=1"},
+		{"fun(a) { a }(1, 2)", "wrong number of arguments: want=1, got=2"},
+		{"fun(a, b = 2) { a }()", "wrong number of arguments: want 1 to 2, got=0"},
+		{"fun(a, b, ...c) { a }(1)", "wrong number of arguments: want at least 2, got=1"},
+		{"fun(a = b) { a }()", "identifier not found: b"},
 	}
 
-	return true
-}
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
 
-func testNullObject(t *testing.T, obj object.Object) bool {
-	if obj != NULL {
-		t.Errorf("object is not null got=%T (%+v)", obj, obj)
-		return false
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
 	}
-	return true
 }
 
-func TestStringLiteral(t *testing.T) {
-	input := `"Hello World"`
-	evaluated := testEval(input)
-	str, ok := evaluated.(*object.String)
-	if !ok {
-		t.Fatalf("object is not String got=%T (%+v)", evaluated, evaluated)
+func TestArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"var double = (x) => x * 2; double(4)", 8},
+		{"var double = x => x * 2; double(4)", 8},
+		{"var add = (a, b = 10) => { return a + b }; add(1)", 11},
+		{"var apply = fun(f, x) { f(x) }; apply(x => x - 1, 5)", 4},
+		{"var add = (x) => (y) => x + y; add(2)(3)", 5},
+		{"(() => 7)()", 7},
+		{"var count = (...xs) => len(xs); count(1, 2, 3)", 3},
+		{"var x = (1 + 2) * 3; x", 9},
 	}
-	if str.Value != "Hello World" {
-		t.Errorf("String has wrong value got=%q", str.Value)
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
 	}
 }
 
-func TestStringConcatenation(t *testing.T) {
-	input := `"Hello" + " " + "World"`
-	evaluated := testEval(input)
-	str, ok := evaluated.(*object.String)
-	if !ok {
-		t.Fatalf("object is not String got=%T (%+v)", evaluated, evaluated)
-	}
-	if str.Value != "Hello World" {
-		t.Errorf("String has wrong value got=%q", str.Value)
-	}
+func TestClosures(t *testing.T) {
+	input := `
+	var add = fun(x) {
+		fun(y) {x + y};
+	};
+	var addTwo = add(2)
+	addTwo(2)
+	`
+	testIntegerObject(t, testEval(t, input), 4)
 }
 
-func TestBuiltinFunctions(t *testing.T) {
+func TestBlockScoping(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{`len("")`, 0},
-		{`len("four")`, 4},
+		{"var x = 1; if (true) { var x = 2 }; x", 1},
+		{"var x = 1; if (true) { x = 2 }; x", 2},
+		{"var x = 1; if (false) { 0 } else { var x = 3; x }", 3},
+		{"var x = 1; if (false) { 0 } else { var x = 3 }; x", 1},
+		{"var x = 1; if (true) { if (true) { x = x + 10 } }; x", 11},
+		{"var f = fun() { if (true) { var y = 5 }; y }; f()", "identifier not found: y"},
+		{"var total = 0; for (n in [1, 2, 3]) { var double = n * 2; total = total + double }; total", 12},
+		{"var i = 0; while (i < 3) { var seen = i; i = i + 1 }; seen", "identifier not found: seen"},
+		{"const c = 1; if (true) { const c = 2; c }", 2},
+		{"var x = 1; match (0) { _ => if (true) { var x = 9 } }; x", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong message! exptected=%q got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestTryExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"try { 1 } catch (e) { 2 }", 1},
+		{"try { throw 5 } catch (e) { e * 2 }", 10},
+		{`try { throw "boom" } catch (e) { len(e) }`, 4},
+		{`try { 1 + true } catch (e) { e["message"] }`, "type mismatch: INTEGER + BOOLEAN"},
+		{`try { len(1) } catch (e) { e["message"] }`, "argument to `len` not supported, got INTEGER"},
+		{"var f = fun() { throw 3 }; try { f() } catch (e) { e }", 3},
+		{"try { try { throw 1 } catch (e) { throw e + 1 } } catch (e) { e }", 2},
+		{"var x = 0; try { x = 1 } finally { x = x + 10 }; x", 11},
+		{"var x = 0; try { throw 1 } catch (e) { x = e } finally { x = x + 10 }; x", 11},
+		{"var x = 0; var f = fun() { try { return 1 } finally { x = 5 } }; f() + x", 6},
+		{"var f = fun() { try { return 1 } finally { return 2 } }; f()", 2},
+		{"var f = fun() { try { throw 1 } catch (e) { return e + 1 } }; f()", 2},
+		{"var n = 0; for (x in [1, 2, 3]) { try { if (x == 2) { break } } finally { n = n + 1 } }; n", 2},
+		{"try { throw 1 } catch (e) { null }", nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("String has wrong value. got=%q, want=%q", str.Value, expected)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestUncaughtErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`throw "boom"`, "boom"},
+		{"throw [1, 2]", "[1, 2]"},
+		{"try { throw 1 } finally { 2 }", "1"},
+		{"try { 1 } catch (e) { 2 } finally { missing }", "identifier not found: missing"},
+		{"try { throw 1 } catch (e) { throw e + 1 }", "2"},
+		{"throw missing", "identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestStructs(t *testing.T) {
+	point := `struct Point {
+		x, y = 0
+		fun sum() { self.x + self.y }
+		fun move(dx, dy = 0) { self.x = self.x + dx; self.y = self.y + dy; self }
+		fun scaled(n) { Point(self.x * n, self.y * n) }
+	}
+	`
+
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{point + "var p = Point(1, 2); p.x", 1},
+		{point + "var p = Point(1, 2); p.y", 2},
+		{point + "Point(4).y", 0},
+		{point + "Point(1, 2).sum()", 3},
+		{point + "var p = Point(1, 2); p.x = 10; p.sum()", 12},
+		{point + "var p = Point(1, 2); p.move(1).move(1, 1); p.sum()", 6},
+		{point + "Point(1, 2).scaled(3).sum()", 9},
+		{point + "var s = Point(1, 2).sum; s()", 3},
+		{point + "var ps = [Point(1, 2), Point(3, 4)]; ps[1].x", 3},
+		{"struct Counter { n = 0; fun inc() { self.n = self.n + 1 } }; var c = Counter(); c.inc(); c.inc(); c.n", 2},
+		{"struct Box { v; fun get(self) { self } }; Box(1).get(7)", 7},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"struct P { x, y }; P(1)", "wrong number of arguments: want=2, got=1"},
+		{"struct P { x }; P(1).z", "unknown member z on P"},
+		{"struct P { x; fun f() { 1 } }; var p = P(1); p.f = 2", "unknown field f on P"},
+		{"struct P { x }; P(1).y = 2", "unknown field y on P"},
+		{"var a = 5; a.x", "unknown member x on INTEGER"},
+		{"var a = 5; a.x = 1", "member assignment not supported: INTEGER"},
+		{"struct P { x; fun f() { missing } }; P(1).f()", "identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestInstanceInspect(t *testing.T) {
+	evaluated := testEval(t, `struct P { name, tags = [] }; P("a")`)
+	if evaluated.Inspect() != "P{name: a, tags: []}" {
+		t.Errorf("wrong inspect. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestBuiltinMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"sloth".len()`, 5},
+		{`"héllo".len()`, 5},
+		{`"Sloth".upper()`, "SLOTH"},
+		{`"Sloth".lower()`, "sloth"},
+		{`"  sloth ".trim()`, "sloth"},
+		{`"a,b,c".split(",").len()`, 3},
+		{`"a,b,c".split(",")[1]`, "b"},
+		{`"sloth".contains("lot")`, true},
+		{`"sloth".contains("x")`, false},
+		{"[1, 2, 3].len()", 3},
+		{"var xs = [1]; xs.push(2, 3); xs.len()", 3},
+		{`[1, 2, 3].join("-")`, "1-2-3"},
+		{"[1, 2, 3].contains(2)", true},
+		{`["a", "b"].contains("c")`, false},
+		{"[1, 2, 3].map(x => x * 2)[2]", 6},
+		{"[1, 2, 3, 4].filter(x => x % 2 == 0).map(x => x * 10).len()", 2},
+		{`[1, 2, 3].map(fun(x) { x + 1 }).join(",")`, "2,3,4"},
+		{`{"a": 1, "b": 2}.keys().join(",")`, "a,b"},
+		{`{"a": 1, "b": 2}.values()[1]`, 2},
+		{`{"a": 1}.has("a")`, true},
+		{`{"a": 1}.has("b")`, false},
+		{`{"a": 1, "b": 2}.len()`, 2},
+		{`var up = "abc".upper; up()`, "ABC"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("String has wrong value. got=%q, want=%q", str.Value, expected)
+			}
+		}
+	}
+}
+
+func TestBuiltinMethodErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`"a".upper(1)`, "wrong number of arguments: want=0, got=1"},
+		{`"a".split(1)`, "argument to `split` not supported, got INTEGER"},
+		{"[1].join(1)", "argument to `join` not supported, got INTEGER"},
+		{"[1].map()", "wrong number of arguments: want=1, got=0"},
+		{"[1].map(1)", "not a function: INTEGER"},
+		{"[1].map(x => x + true)", "type mismatch: INTEGER + BOOLEAN"},
+		{"{}.has([])", "unusable as hash key: ARRAY"},
+		{`"a".reverse()`, "unknown member reverse on STRING"},
+		{"true.len()", "unknown member len on BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func writeModules(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestImportStatements(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"math.sloth": `
+			export const pi = 3
+			export var square = fun(x) { x * x }
+			var hidden = 1
+			export var counter = 0
+			export var bump = fun() { counter = counter + 1 }
+		`,
+		"shapes.sloth": `
+			import "lib/util.sloth" as util
+			export struct Circle { r; fun area() { util.scale(self.r * self.r) } }
+		`,
+		"lib/util.sloth": `
+			import "../math.sloth" as m
+			export var scale = fun(x) { x * m.pi }
+			export var [first, ...others] = [1, 2, 3]
+			export var load = fun() { import "data.sloth" as d; d.v }
+		`,
+		"lib/data.sloth": `export var v = 7`,
+	})
+	math := filepath.Join(dir, "math.sloth")
+	shapes := filepath.Join(dir, "shapes.sloth")
+	util := filepath.Join(dir, "lib", "util.sloth")
+
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{fmt.Sprintf(`import "%s" as m; m.pi`, math), 3},
+		{fmt.Sprintf(`import "%s" as m; m.square(4)`, math), 16},
+		{fmt.Sprintf(`import "%s" as s; s.Circle(2).area()`, shapes), 12},
+		{fmt.Sprintf(`import "%s" as u; u.first + len(u.others)`, util), 3},
+		{fmt.Sprintf(`import "%s" as u; u.load()`, util), 7},
+		{fmt.Sprintf(`import "%s" as a; import "%s" as b; a.bump(); b.bump(); a.counter`, math, math), 2},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+
+	evaluated := testEval(t, fmt.Sprintf(`import "%s" as m; m`, math))
+	if evaluated.Inspect() != fmt.Sprintf("module %q", math) {
+		t.Errorf("wrong inspect. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"a.sloth":      `import "b.sloth" as b; export var x = 1`,
+		"b.sloth":      `import "a.sloth" as a; export var y = 2`,
+		"self.sloth":   `import "self.sloth" as me`,
+		"lib.sloth":    `var secret = 1; export var open = 2`,
+		"broken.sloth": `try { 1 }`,
+		"fails.sloth":  `export var x = 1 + true`,
+	})
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{
+			fmt.Sprintf(`import "%s" as a`, path("a.sloth")),
+			fmt.Sprintf("import cycle: %s -> %s -> %s", path("a.sloth"), path("b.sloth"), path("a.sloth")),
+		},
+		{
+			fmt.Sprintf(`import "%s" as me`, path("self.sloth")),
+			fmt.Sprintf("import cycle: %s -> %s", path("self.sloth"), path("self.sloth")),
+		},
+		{
+			fmt.Sprintf(`import "%s" as lib; lib.secret`, path("lib.sloth")),
+			fmt.Sprintf("module %s has no export secret", path("lib.sloth")),
+		},
+		{
+			fmt.Sprintf(`import "%s" as lib; lib.open = 3`, path("lib.sloth")),
+			"member assignment not supported: MODULE",
+		},
+		{
+			fmt.Sprintf(`import "%s" as b`, path("broken.sloth")),
+			fmt.Sprintf("cannot import %s: expected catch or finally after try block", path("broken.sloth")),
+		},
+		{
+			fmt.Sprintf(`import "%s" as f`, path("fails.sloth")),
+			"type mismatch: INTEGER + BOOLEAN",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+
+	missing := path("missing.sloth")
+	evaluated := testEval(t, fmt.Sprintf(`import "%s" as m`, missing))
+	errObj, ok := evaluated.(*object.Error)
+	if !ok || !strings.HasPrefix(errObj.Message, "cannot import "+missing+": ") {
+		t.Errorf("wrong error for missing module. got=%+v", evaluated)
+	}
+}
+
+func TestWhileStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"var i = 0; while (i < 10) { i = i + 1 }; i", 10},
+		{"while (false) { 1 }", nil},
+		{"var i = 0; while (true) { i = i + 1; if (i > 4) { break } }; i", 5},
+		{`var i = 0; var sum = 0;
+		while (i < 10) {
+			i = i + 1;
+			if (i > 5) { continue }
+			sum = sum + i;
+		}
+		sum`, 15},
+		{"var f = fun() { var i = 0; while (true) { i = i + 1; if (i == 3) { return i } } }; f()", 3},
+		{"var i = 0; while (i < 100000) { i = i + 1 }; i", 100000},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestForStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"var last = fun(xs) { var v = 0; for (x in xs) { return x }; v }; last([7, 8])", 7},
+		{"var find = fun(xs, t) { for (i, x in xs) { if (x == t) { return i } }; -1 }; find([4, 5, 6], 6)", 2},
+		{"var find = fun(xs, t) { for (i, x in xs) { if (x == t) { return i } }; -1 }; find([4, 5, 6], 9)", -1},
+		{`var at = fun(s, n) { for (i, ch in s) { if (i == n) { return len(ch) } } }; at("sloth", 2)`, 1},
+		{`var get = fun(h) { for (k, v in h) { if (v > 1) { return v } } }; get({"a": 1, "b": 2})`, 2},
+		{`var first = fun(h) { for (k in h) { return k } }; first({1: "a", 2: "b"})`, 1},
+		{"for (x in [1, 2, 3]) { if (x == 2) { break } }", nil},
+		{"var f = fun() { for (x in [1, 2, 3]) { if (x < 3) { continue }; return x } }; f()", 3},
+		{"for (x in []) { x }", nil},
+		{"var f = fun() { for (x in range(3)) { if (x == 2) { return x } } }; f()", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestForStatementErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"for (x in 5) { x }", "cannot iterate over INTEGER"},
+		{"for (x in [1]) { x }; x", "identifier not found: x"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"break", "break outside loop"},
+		{"if (true) { continue }", "continue outside loop"},
+		{"while (true) { fun() { break }() }", "break outside loop"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message! exptected=%q got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func testEval(t *testing.T, input string) object.Object {
+	t.Helper()
+
+	l := lexer.New(input)
+	p := parser.New(l)
+	env := object.NewEnviroment()
+	program := p.ParseProgram()
+
+	if errors := p.Errors(); len(errors) != 0 {
+		t.Errorf("parser has %d errors for %q", len(errors), input)
+		for _, msg := range errors {
+			t.Errorf("parser error: %q", msg)
+		}
+	}
+
+	return Eval(program, env)
+}
+
+func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
+	result, ok := obj.(*object.Integer)
+
+	if !ok {
+		t.Errorf("object is not integer got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Value != expected {
+		t.Errorf("pbject as wrong value got=%d, want=%d", result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
+func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
+	result, ok := obj.(*object.Float)
+
+	if !ok {
+		t.Errorf("object is not float got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Value != expected {
+		t.Errorf("object has wrong value got=%f, want=%f", result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
+func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
+	result, ok := obj.(*object.Boolean)
+
+	if !ok {
+		t.Errorf("object is not integer got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Value != expected {
+		t.Errorf("pbject as wrong value got=%t, want=%t", result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
+func testNullObject(t *testing.T, obj object.Object) bool {
+	if obj != NULL {
+		t.Errorf("object is not null got=%T (%+v)", obj, obj)
+		return false
+	}
+	return true
+}
+
+func TestStringLiteral(t *testing.T) {
+	input := `"Hello World"`
+	evaluated := testEval(t, input)
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String got=%T (%+v)", evaluated, evaluated)
+	}
+	if str.Value != "Hello World" {
+		t.Errorf("String has wrong value got=%q", str.Value)
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	input := `"say \"hi\"\n" + "\tbye"`
+	evaluated := testEval(t, input)
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String got=%T (%+v)", evaluated, evaluated)
+	}
+	if str.Value != "say \"hi\"\n\tbye" {
+		t.Errorf("String has wrong value got=%q", str.Value)
+	}
+}
+
+func TestRawStringLiteral(t *testing.T) {
+	input := "var query = `\n\tSELECT *\n\tFROM users\n`; query + \";\""
+	evaluated := testEval(t, input)
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String got=%T (%+v)", evaluated, evaluated)
+	}
+	if str.Value != "SELECT *\nFROM users;" {
+		t.Errorf("String has wrong value got=%q", str.Value)
+	}
+}
+
+func TestInterpolatedStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`var a = 1; var b = 2; "total: ${a + b}"`, "total: 3"},
+		{`"${1.5} ${true} ${[1, 2]}"`, "1.5 true [1, 2]"},
+		{`var name = "sloth"; "hi ${name}, ${"nested ${name}"}"`, "hi sloth, nested sloth"},
+		{`"${ {"k": "v"}["k"] }"`, "v"},
+		{`"price: \${5}"`, "price: ${5}"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. expected=%q, got=%q", tt.expected, str.Value)
+		}
+	}
+}
+
+func TestStringConcatenation(t *testing.T) {
+	input := `"Hello" + " " + "World"`
+	evaluated := testEval(t, input)
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String got=%T (%+v)", evaluated, evaluated)
+	}
+	if str.Value != "Hello World" {
+		t.Errorf("String has wrong value got=%q", str.Value)
+	}
+}
+
+func TestBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`len("")`, 0},
+		{`len("four")`, 4},
+		{`len("héllo")`, 5},
 		{`len(1)`, "argument to `len` not supported, got INTEGER"},
+		{`len(range(5))`, 5},
+		{`len(range(2, 5))`, 3},
+		{`len(range(10, 0, -2))`, 5},
+		{`range(1, 5, 0)`, "`range` step must not be zero"},
+		{`range("a")`, "argument to `range` not supported, got STRING"},
 	}
 
 	for _, tt := range tests {
-		evaluated := testEval(tt.input)
+		evaluated := testEval(t, tt.input)
 
 		switch expected := tt.expected.(type) {
 		case int:
@@ -312,7 +1319,7 @@ func TestBuiltinFunctions(t *testing.T) {
 
 func TestArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 +3]"
-	evaluated := testEval(input)
+	evaluated := testEval(t, input)
 
 	result, ok := evaluated.(*object.Array)
 
@@ -326,4 +1333,194 @@ func TestArrayLiterals(t *testing.T) {
 	testIntegerObject(t, result.Elements[0], 1)
 	testIntegerObject(t, result.Elements[1], 4)
 	testIntegerObject(t, result.Elements[2], 6)
+
+	evaluated = testEval(t, "[fun() {}()]")
+	if evaluated.Inspect() != "[null]" {
+		t.Errorf("wrong inspect. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3, 4][1:3]", []int64{2, 3}},
+		{"[1, 2, 3, 4][2:]", []int64{3, 4}},
+		{"[1, 2, 3, 4][:2]", []int64{1, 2}},
+		{"[1, 2, 3, 4][:]", []int64{1, 2, 3, 4}},
+		{"[1, 2, 3][1:100]", []int64{2, 3}},
+		{"[1, 2, 3][5:]", []int64{}},
+		{"[1, 2, 3][2:1]", []int64{}},
+		{"var xs = [1, 2, 3]; var ys = xs[:]; ys[0] = 9; xs", []int64{1, 2, 3}},
+		{`"sloth"[1:3]`, "lo"},
+		{`"sloth"[2:]`, "oth"},
+		{`"sloth"[:0]`, ""},
+		{`"sloth"[3:99]`, "th"},
+		{`"héllo"[1:2]`, "é"},
+		{"[1][true:]", "slice index must be INTEGER, got BOOLEAN"},
+		{"5[1:]", "slice operator not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case []int64:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements. want=%d, got=%d", len(expected), len(array.Elements))
+				continue
+			}
+			for i, el := range expected {
+				testIntegerObject(t, array.Elements[i], el)
+			}
+		case string:
+			switch result := evaluated.(type) {
+			case *object.String:
+				if result.Value != expected {
+					t.Errorf("String has wrong value. got=%q, want=%q", result.Value, expected)
+				}
+			case *object.Error:
+				if result.Message != expected {
+					t.Errorf("wrong message! exptected=%q got=%q", expected, result.Message)
+				}
+			default:
+				t.Errorf("object is not String or Error. got=%T (%+v)", evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3][0]", 1},
+		{"[1, 2, 3][2]", 3},
+		{"[1, 2, 3][3]", nil},
+		{"[1, 2, 3][-1]", 3},
+		{"[1, 2, 3][-3]", 1},
+		{"[1, 2, 3][-4]", nil},
+		{"[][-1]", nil},
+		{`"sloth"[0]`, "s"},
+		{`"sloth"[4]`, "h"},
+		{`"sloth"[5]`, nil},
+		{`"sloth"[-1]`, "h"},
+		{`"sloth"[-5]`, "s"},
+		{`"sloth"[-6]`, nil},
+		{`"héllo"[1]`, "é"},
+		{`var s = "abc"; s[len(s) - 1]`, "c"},
+		{`var s = "héllo"; s[len(s) - 1]`, "o"},
+		{"[1, 2, 3, 4][-2:]", []int64{3, 4}},
+		{"[1, 2, 3, 4][:-1]", []int64{1, 2, 3}},
+		{"[1, 2, 3, 4][-10:2]", []int64{1, 2}},
+		{`"sloth"[-3:-1]`, "ot"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("String has wrong value. got=%q, want=%q", str.Value, expected)
+			}
+		case []int64:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements. want=%d, got=%d", len(expected), len(array.Elements))
+				continue
+			}
+			for i, el := range expected {
+				testIntegerObject(t, array.Elements[i], el)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestHashLiterals(t *testing.T) {
+	input := `var two = "two";
+	{
+		"one": 10 - 9,
+		two: 1 + 1,
+		"thr" + "ee": 6 / 2,
+		4: 4,
+		true: 5,
+		false: 6
+	}`
+
+	evaluated := testEval(t, input)
+	result, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("Eval didn't return Hash got=%T (%+v)", evaluated, evaluated)
+	}
+
+	expected := map[object.HashKey]int64{
+		(&object.String{Value: "one"}).HashKey():   1,
+		(&object.String{Value: "two"}).HashKey():   2,
+		(&object.String{Value: "three"}).HashKey(): 3,
+		(&object.Integer{Value: 4}).HashKey():      4,
+		TRUE.HashKey():                             5,
+		FALSE.HashKey():                            6,
+	}
+
+	if len(result.Pairs) != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs got=%d", len(result.Pairs))
+	}
+
+	for expectedKey, expectedValue := range expected {
+		pair, ok := result.Pairs[expectedKey]
+		if !ok {
+			t.Errorf("no pair for given key in Pairs")
+		}
+
+		testIntegerObject(t, pair.Value, expectedValue)
+	}
+
+	if result.Inspect() != "{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}" {
+		t.Errorf("Hash has wrong Inspect got=%q", result.Inspect())
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`var key = "foo"; {"foo": 5}[key]`, 5},
+		{`{}["foo"]`, nil},
+		{`{5: 5}[5]`, 5},
+		{`{true: 5}[true]`, 5},
+		{`{false: 5}[false]`, 5},
+		{`len({"a": 1, "b": 2})`, 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
 }
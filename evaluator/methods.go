@@ -0,0 +1,191 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/nazeemnato/sloth/object"
+)
+
+// methods holds the methods of the built-in types. Each one gets the
+// receiver as its first argument. The table is filled in init because the
+// higher-order methods call back into the evaluator.
+var methods map[object.ObjectType]map[string]*object.Builtin
+
+func init() {
+	methods = map[object.ObjectType]map[string]*object.Builtin{
+		object.STRING_OBJ: {
+			"len": builtins["len"],
+			"upper": stringMethod("upper", 0, func(s string, args []object.Object) object.Object {
+				return &object.String{Value: strings.ToUpper(s)}
+			}),
+			"lower": stringMethod("lower", 0, func(s string, args []object.Object) object.Object {
+				return &object.String{Value: strings.ToLower(s)}
+			}),
+			"trim": stringMethod("trim", 0, func(s string, args []object.Object) object.Object {
+				return &object.String{Value: strings.TrimSpace(s)}
+			}),
+			"split": stringMethod("split", 1, func(s string, args []object.Object) object.Object {
+				parts := strings.Split(s, args[0].(*object.String).Value)
+				elements := make([]object.Object, len(parts))
+				for i, part := range parts {
+					elements[i] = &object.String{Value: part}
+				}
+				return &object.Array{Elements: elements}
+			}),
+			"contains": stringMethod("contains", 1, func(s string, args []object.Object) object.Object {
+				return nativeBooltoBooleanObject(strings.Contains(s, args[0].(*object.String).Value))
+			}),
+		},
+		object.ARRAY_OBJ: {
+			"len": builtins["len"],
+			"push": {
+				Fn: func(args ...object.Object) object.Object {
+					array := args[0].(*object.Array)
+					array.Elements = append(array.Elements, args[1:]...)
+					return array
+				},
+			},
+			"join": {
+				Fn: func(args ...object.Object) object.Object {
+					if err := checkMethodArgs(args, 1); err != nil {
+						return err
+					}
+					sep, ok := args[1].(*object.String)
+					if !ok {
+						return newError("argument to `join` not supported, got %s", args[1].Type())
+					}
+					parts := []string{}
+					for _, el := range args[0].(*object.Array).Elements {
+						parts = append(parts, el.Inspect())
+					}
+					return &object.String{Value: strings.Join(parts, sep.Value)}
+				},
+			},
+			"contains": {
+				Fn: func(args ...object.Object) object.Object {
+					if err := checkMethodArgs(args, 1); err != nil {
+						return err
+					}
+					for _, el := range args[0].(*object.Array).Elements {
+						if evalInfExpression("==", el, args[1]) == TRUE {
+							return TRUE
+						}
+					}
+					return FALSE
+				},
+			},
+			"map": {
+				Fn: func(args ...object.Object) object.Object {
+					if err := checkMethodArgs(args, 1); err != nil {
+						return err
+					}
+					elements := []object.Object{}
+					for _, el := range args[0].(*object.Array).Elements {
+						val := applyFunction(args[1], []object.Object{el})
+						if isError(val) {
+							return val
+						}
+						elements = append(elements, val)
+					}
+					return &object.Array{Elements: elements}
+				},
+			},
+			"filter": {
+				Fn: func(args ...object.Object) object.Object {
+					if err := checkMethodArgs(args, 1); err != nil {
+						return err
+					}
+					elements := []object.Object{}
+					for _, el := range args[0].(*object.Array).Elements {
+						keep := applyFunction(args[1], []object.Object{el})
+						if isError(keep) {
+							return keep
+						}
+						if isTruthy(keep) {
+							elements = append(elements, el)
+						}
+					}
+					return &object.Array{Elements: elements}
+				},
+			},
+		},
+		object.HASH_OBJ: {
+			"len": builtins["len"],
+			"keys": {
+				Fn: func(args ...object.Object) object.Object {
+					if err := checkMethodArgs(args, 0); err != nil {
+						return err
+					}
+					hash := args[0].(*object.Hash)
+					keys := []object.Object{}
+					for _, key := range hash.Keys {
+						keys = append(keys, hash.Pairs[key].Key)
+					}
+					return &object.Array{Elements: keys}
+				},
+			},
+			"values": {
+				Fn: func(args ...object.Object) object.Object {
+					if err := checkMethodArgs(args, 0); err != nil {
+						return err
+					}
+					hash := args[0].(*object.Hash)
+					values := []object.Object{}
+					for _, key := range hash.Keys {
+						values = append(values, hash.Pairs[key].Value)
+					}
+					return &object.Array{Elements: values}
+				},
+			},
+			"has": {
+				Fn: func(args ...object.Object) object.Object {
+					if err := checkMethodArgs(args, 1); err != nil {
+						return err
+					}
+					key, ok := args[1].(object.Hashable)
+					if !ok {
+						return newError("unusable as hash key: %s", args[1].Type())
+					}
+					_, ok = args[0].(*object.Hash).Pairs[key.HashKey()]
+					return nativeBooltoBooleanObject(ok)
+				},
+			},
+		},
+	}
+}
+
+// checkMethodArgs checks the number of arguments passed to a method, not
+// counting the receiver.
+func checkMethodArgs(args []object.Object, want int) *object.Error {
+	if got := len(args) - 1; got != want {
+		return newError("wrong number of arguments: want=%d, got=%d", want, got)
+	}
+	return nil
+}
+
+// stringMethod wraps fn, which takes the receiver's value and want string
+// arguments.
+func stringMethod(name string, want int, fn func(string, []object.Object) object.Object) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkMethodArgs(args, want); err != nil {
+				return err
+			}
+			for _, arg := range args[1:] {
+				if arg.Type() != object.STRING_OBJ {
+					return newError("argument to `%s` not supported, got %s", name, arg.Type())
+				}
+			}
+			return fn(args[0].(*object.String).Value, args[1:])
+		},
+	}
+}
+
+// bindMethod returns method with obj filled in as its receiver.
+func bindMethod(method *object.Builtin, obj object.Object) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			return method.Fn(append([]object.Object{obj}, args...)...)
+		},
+	}
+}
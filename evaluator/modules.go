@@ -0,0 +1,91 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nazeemnato/sloth/ast"
+	"github.com/nazeemnato/sloth/lexer"
+	"github.com/nazeemnato/sloth/object"
+	"github.com/nazeemnato/sloth/parser"
+)
+
+var (
+	// modules caches every module loaded so far by absolute path, so a
+	// file is only evaluated once however often it is imported.
+	modules = map[string]*object.Module{}
+
+	// importing is the chain of modules being loaded, innermost last. It
+	// finds import cycles.
+	importing = []string{}
+)
+
+func evalImportStatement(node *ast.ImportStatement, env *object.Enviroment) object.Object {
+	module := importModule(node.Path, env.Dir())
+	if isError(module) {
+		return module
+	}
+
+	if res := env.Set(node.Alias.Value, module); isError(res) {
+		return res
+	}
+	return nil
+}
+
+// importModule loads the module at name, relative to dir, the directory of
+// the importing module. An empty dir means the working directory.
+func importModule(name, dir string) object.Object {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return newError("cannot import %s: %s", name, err)
+	}
+
+	if module, ok := modules[abs]; ok {
+		return module
+	}
+
+	for i, loading := range importing {
+		if loading == abs {
+			cycle := append(append([]string{}, importing[i:]...), abs)
+			return newError("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	source, err := os.ReadFile(abs)
+	if err != nil {
+		return newError("cannot import %s: %s", name, err)
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return newError("cannot import %s: %s", name, strings.Join(p.Errors(), "; "))
+	}
+
+	importing = append(importing, abs)
+	defer func() { importing = importing[:len(importing)-1] }()
+
+	env := object.NewEnviroment()
+	env.SetDir(filepath.Dir(abs))
+	if result := Eval(program, env); isError(result) {
+		return result
+	}
+
+	module := &object.Module{Path: abs, Env: env, Exports: map[string]bool{}}
+	for _, stmt := range program.Statements {
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			for _, name := range export.Names() {
+				module.Exports[name] = true
+			}
+		}
+	}
+
+	modules[abs] = module
+	return module
+}
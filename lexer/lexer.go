@@ -1,21 +1,53 @@
 package lexer
 
-import "github.com/nazeemnato/sloth/token"
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nazeemnato/sloth/token"
+)
 
 type Lexer struct {
 	input        string
 	position     int
 	readPosition int
 	ch           byte
+	line         int
+	comments     []token.Token
+
+	// brace depth of every ${} interpolation we are inside of, innermost last
+	interpolations []int
 }
 
+// stringEnd tells what stopped readString.
+type stringEnd int
+
+const (
+	stringClosed        stringEnd = iota // the closing quote
+	stringInterpolation                  // the ${ of an interpolation
+	stringUnterminated                   // the end of the input
+)
+
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
+// Clone returns an independent copy of the lexer so the parser can look
+// ahead without consuming any input.
+func (l *Lexer) Clone() *Lexer {
+	c := *l
+	c.comments = nil
+	c.interpolations = append([]int(nil), l.interpolations...)
+	return &c
+}
+
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+	}
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -26,14 +58,28 @@ func (l *Lexer) readChar() {
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
 	l.skipWhitespace()
+	line := l.line
+
+	tok := l.readToken()
+	if tok.Line == 0 {
+		tok.Line = line
+	}
+	return tok
+}
+
+func (l *Lexer) readToken() token.Token {
+	var tok token.Token
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.EQ, Literal: string(ch) + string(ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(token.ASSIGN, l.ch)
 		}
@@ -42,9 +88,24 @@ func (l *Lexer) NextToken() token.Token {
 	case ')':
 		tok = newToken(token.RPAREN, l.ch)
 	case '{':
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1]++
+		}
 		tok = newToken(token.LBRACE, l.ch)
 	case '}':
-		tok = newToken(token.RBRACE, l.ch)
+		if n := len(l.interpolations); n > 0 && l.interpolations[n-1] == 0 {
+			// this brace closes an interpolation, the string carries on
+			l.interpolations = l.interpolations[:n-1]
+			tok = l.readStringToken(token.INTERP_TAIL, token.INTERP_MIDDLE, l.position)
+			if tok.Type == token.ILLEGAL {
+				return tok
+			}
+		} else {
+			if n > 0 {
+				l.interpolations[n-1]--
+			}
+			tok = newToken(token.RBRACE, l.ch)
+		}
 	case '[':
 		tok = newToken(token.LBRACKET, l.ch)
 	case ']':
@@ -61,21 +122,90 @@ func (l *Lexer) NextToken() token.Token {
 		} else {
 			tok = newToken(token.BANG, l.ch)
 		}
+	case '&':
+		if l.peekChar() == '&' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.AND, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
+	case '|':
+		if l.peekChar() == '|' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.OR, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
 	case '/':
-		tok = newToken(token.SLASH, l.ch)
+		switch l.peekChar() {
+		case '/':
+			l.comments = append(l.comments, token.Token{Type: token.COMMENT, Literal: l.readLineComment()})
+			return l.NextToken()
+		case '*':
+			comment, ok := l.readBlockComment()
+			if !ok {
+				return token.Token{Type: token.ILLEGAL, Literal: comment}
+			}
+			l.comments = append(l.comments, token.Token{Type: token.COMMENT, Literal: comment})
+			return l.NextToken()
+		default:
+			tok = newToken(token.SLASH, l.ch)
+		}
 	case '*':
-		tok = newToken(token.ASTERISK, l.ch)
+		if l.peekChar() == '*' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.POWER, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.ASTERISK, l.ch)
+		}
+	case '%':
+		tok = newToken(token.PERCENT, l.ch)
 	case '<':
-		tok = newToken(token.LT, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.LT_EQ, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.LT, l.ch)
+		}
 	case '>':
-		tok = newToken(token.GT, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.GT_EQ, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.GT, l.ch)
+		}
+	case '.':
+		if l.peekChar() == '.' && l.readPosition+1 < len(l.input) && l.input[l.readPosition+1] == '.' {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else {
+			tok = newToken(token.DOT, l.ch)
+		}
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
 	case ';':
 		tok = newToken(token.SEMICOLN, l.ch)
+	case ':':
+		tok = newToken(token.COLON, l.ch)
 	case '"':
+		tok = l.readStringToken(token.STRING, token.INTERP_HEAD, l.position)
+		if tok.Type == token.ILLEGAL {
+			return tok
+		}
+	case '`':
+		position := l.position
+		str, ok := l.readRawString()
+		if !ok {
+			return token.Token{Type: token.ILLEGAL, Literal: l.input[position:l.position]}
+		}
 		tok.Type = token.STRING
-		tok.Literal = l.readString()
+		tok.Literal = str
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -86,8 +216,7 @@ func (l *Lexer) NextToken() token.Token {
 			tok.Type = token.LookupIdent(tok.Literal)
 			return tok
 		} else if isDigit(l.ch) {
-			tok.Type = token.INT
-			tok.Literal = l.readNumber()
+			tok.Literal, tok.Type = l.readNumber()
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, l.ch)
@@ -122,12 +251,24 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
-func (l *Lexer) readNumber() string {
+func (l *Lexer) readNumber() (string, token.TokenType) {
 	position := l.position
+	tokenType := token.TokenType(token.INT)
+
 	for isDigit(l.ch) {
 		l.readChar()
 	}
-	return l.input[position:l.position]
+
+	// a dot only belongs to the number when a digit follows it
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		tokenType = token.FLOAT
+		l.readChar()
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
+
+	return l.input[position:l.position], tokenType
 }
 
 func isDigit(ch byte) bool {
@@ -142,15 +283,192 @@ func (l *Lexer) peekChar() byte {
 	}
 }
 
-func (l *Lexer) readString() string {
+// Comments returns every comment read so far, in source order, so that
+// tooling can put them back where they belong.
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
+}
+
+func (l *Lexer) readLineComment() string {
+	position := l.position
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+	return l.input[position:l.position]
+}
+
+// readBlockComment reads a /* */ comment and reports false when the input
+// ends before the comment is closed.
+func (l *Lexer) readBlockComment() (string, bool) {
+	position := l.position
+	l.readChar()
+
+	for {
+		l.readChar()
+		if l.ch == 0 {
+			return l.input[position:l.position], false
+		}
+		if l.ch == '*' && l.peekChar() == '/' {
+			l.readChar()
+			l.readChar()
+			return l.input[position:l.position], true
+		}
+	}
+}
+
+// readStringToken reads the rest of a string literal that started at
+// position. Text that runs up to the closing quote becomes a token of type
+// closed, text cut short by a ${ becomes a token of type open.
+func (l *Lexer) readStringToken(closed, open token.TokenType, position int) token.Token {
+	str, end := l.readString()
+
+	switch end {
+	case stringClosed:
+		return token.Token{Type: closed, Literal: str}
+	case stringInterpolation:
+		l.interpolations = append(l.interpolations, 0)
+		return token.Token{Type: open, Literal: str}
+	default:
+		return token.Token{Type: token.ILLEGAL, Literal: l.input[position:l.position]}
+	}
+}
+
+// readString reads string text and processes its escape sequences until
+// the closing quote, an interpolation or the end of the input.
+func (l *Lexer) readString() (string, stringEnd) {
+	var out strings.Builder
+
+	for {
+		l.readChar()
+		switch l.ch {
+		case '"':
+			return out.String(), stringClosed
+		case 0:
+			return "", stringUnterminated
+		case '$':
+			if l.peekChar() == '{' {
+				l.readChar()
+				return out.String(), stringInterpolation
+			}
+			out.WriteByte(l.ch)
+		case '\\':
+			l.readChar()
+			if l.ch == 0 {
+				return "", stringUnterminated
+			}
+			l.readEscape(&out)
+		default:
+			out.WriteByte(l.ch)
+		}
+	}
+}
+
+// readRawString reads a backtick string verbatim. When the opening backtick
+// ends its line the string is treated as a block and trimmed with
+// trimIndent. It reports false when the closing backtick is missing.
+func (l *Lexer) readRawString() (string, bool) {
 	position := l.position + 1
 
 	for {
 		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
+		if l.ch == '`' {
 			break
 		}
+		if l.ch == 0 {
+			return "", false
+		}
 	}
 
-	return l.input[position:l.position]
-}
\ No newline at end of file
+	str := l.input[position:l.position]
+	if strings.HasPrefix(str, "\n") || strings.HasPrefix(str, "\r\n") {
+		return trimIndent(str), true
+	}
+	return str, true
+}
+
+// trimIndent drops the line break after the opening backtick, a last line
+// holding only the indentation of the closing backtick, and the
+// indentation shared by every non-blank line.
+func trimIndent(str string) string {
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "\r"), "\n")
+	lines := strings.Split(str, "\n")
+
+	if last := lines[len(lines)-1]; strings.TrimSpace(last) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			indent = lead
+			first = false
+			continue
+		}
+		for !strings.HasPrefix(lead, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = strings.TrimRight(line, " \t")
+			continue
+		}
+		lines[i] = strings.TrimPrefix(line, indent)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// readEscape writes the character escaped by the backslash before l.ch.
+// Unknown escapes are kept as they were written.
+func (l *Lexer) readEscape(out *strings.Builder) {
+	switch l.ch {
+	case 'n':
+		out.WriteByte('\n')
+	case 't':
+		out.WriteByte('\t')
+	case 'r':
+		out.WriteByte('\r')
+	case '\\':
+		out.WriteByte('\\')
+	case '"':
+		out.WriteByte('"')
+	case '$':
+		out.WriteByte('$')
+	case 'u':
+		if r, size, ok := l.peekUnicodeEscape(); ok {
+			out.WriteRune(r)
+			for i := 0; i < size; i++ {
+				l.readChar()
+			}
+			return
+		}
+		out.WriteString(`\u`)
+	default:
+		out.WriteByte('\\')
+		out.WriteByte(l.ch)
+	}
+}
+
+// peekUnicodeEscape looks for a {hex} code point after a \u escape and
+// returns the rune together with the number of bytes it spans.
+func (l *Lexer) peekUnicodeEscape() (rune, int, bool) {
+	rest := l.input[l.readPosition:]
+	end := strings.IndexByte(rest, '}')
+	if !strings.HasPrefix(rest, "{") || end < 2 || end > 7 {
+		return 0, 0, false
+	}
+
+	code, err := strconv.ParseUint(rest[1:end], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return 0, 0, false
+	}
+
+	return rune(code), end + 1, true
+}
@@ -15,7 +15,7 @@ func TestNextToken(t *testing.T) {
 
 	var result = add(five,two);
 
-	!-/*5;
+	!-/ *5;
 	5 < 10 > 5;
 
 	if (5 < 10) {
@@ -29,6 +29,15 @@ func TestNextToken(t *testing.T) {
 	"foobar"
 	"foo bar"
 	[1,2];
+	{"foo": "bar"}
+	3.14 + 2.;
+	while (true) { break; continue; }
+	for (i, x in xs) {}
+	a && b || c & d
+	1 <= 2 >= 3 % 4 ** 5
+	null
+	match (x) { [a, ...r] => a }
+	struct P { x } p.x
 	`
 
 	tests := []struct {
@@ -127,7 +136,270 @@ func TestNextToken(t *testing.T) {
 		{token.INT, "2"},
 		{token.RBRACKET, "]"},
 		{token.SEMICOLN, ";"},
-		
+
+		{token.LBRACE, "{"},
+		{token.STRING, "foo"},
+		{token.COLON, ":"},
+		{token.STRING, "bar"},
+		{token.RBRACE, "}"},
+
+		{token.FLOAT, "3.14"},
+		{token.PLUS, "+"},
+		{token.INT, "2"},
+		{token.DOT, "."},
+		{token.SEMICOLN, ";"},
+
+		{token.WHILE, "while"},
+		{token.LPAREN, "("},
+		{token.TRUE, "true"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.BREAK, "break"},
+		{token.SEMICOLN, ";"},
+		{token.CONTINUE, "continue"},
+		{token.SEMICOLN, ";"},
+		{token.RBRACE, "}"},
+
+		{token.FOR, "for"},
+		{token.LPAREN, "("},
+		{token.IDENT, "i"},
+		{token.COMMA, ","},
+		{token.IDENT, "x"},
+		{token.IN, "in"},
+		{token.IDENT, "xs"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+
+		{token.IDENT, "a"},
+		{token.AND, "&&"},
+		{token.IDENT, "b"},
+		{token.OR, "||"},
+		{token.IDENT, "c"},
+		{token.ILLEGAL, "&"},
+		{token.IDENT, "d"},
+
+		{token.INT, "1"},
+		{token.LT_EQ, "<="},
+		{token.INT, "2"},
+		{token.GT_EQ, ">="},
+		{token.INT, "3"},
+		{token.PERCENT, "%"},
+		{token.INT, "4"},
+		{token.POWER, "**"},
+		{token.INT, "5"},
+
+		{token.NULL, "null"},
+
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "r"},
+		{token.RBRACKET, "]"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.RBRACE, "}"},
+		{token.STRUCT, "struct"},
+		{token.IDENT, "P"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "p"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("test[%d] - token type wrong. exected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.exceptedLiteral {
+			t.Fatalf("test[%d] - literal  wrong. exected=%q, got=%q", i, tt.exceptedLiteral, tok.Literal)
+		}
+	}
+}
+
+
+func TestComments(t *testing.T) {
+	input := `// leading comment
+	var x = 10 / 2; // trailing comment
+	/* block
+	   comment */ x
+	/**/`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		exceptedLiteral string
+	}{
+		{token.VAR, "var"},
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "10"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
+		{token.SEMICOLN, ";"},
+		{token.IDENT, "x"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("test[%d] - token type wrong. exected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.exceptedLiteral {
+			t.Fatalf("test[%d] - literal  wrong. exected=%q, got=%q", i, tt.exceptedLiteral, tok.Literal)
+		}
+	}
+
+	expectedComments := []string{
+		"// leading comment",
+		"// trailing comment",
+		"/* block\n\t   comment */",
+		"/**/",
+	}
+
+	comments := l.Comments()
+	if len(comments) != len(expectedComments) {
+		t.Fatalf("wrong number of comments. expected=%d, got=%d", len(expectedComments), len(comments))
+	}
+
+	for i, expected := range expectedComments {
+		if comments[i].Type != token.COMMENT {
+			t.Errorf("comments[%d] - token type wrong. exected=%q, got=%q", i, token.COMMENT, comments[i].Type)
+		}
+		if comments[i].Literal != expected {
+			t.Errorf("comments[%d] - literal wrong. exected=%q, got=%q", i, expected, comments[i].Literal)
+		}
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	l := New("1 /* never closed")
+
+	if tok := l.NextToken(); tok.Type != token.INT {
+		t.Fatalf("token type wrong. exected=%q, got=%q", token.INT, tok.Type)
+	}
+
+	tok := l.NextToken()
+	if tok.Type != token.ILLEGAL {
+		t.Fatalf("token type wrong. exected=%q, got=%q", token.ILLEGAL, tok.Type)
+	}
+	if tok.Literal != "/* never closed" {
+		t.Fatalf("literal wrong. exected=%q, got=%q", "/* never closed", tok.Literal)
+	}
+
+	if tok := l.NextToken(); tok.Type != token.EOF {
+		t.Fatalf("token type wrong. exected=%q, got=%q", token.EOF, tok.Type)
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a\"b"`, `a"b`},
+		{`"line\nnext"`, "line\nnext"},
+		{`"tab\there"`, "tab\there"},
+		{`"back\\slash"`, `back\slash`},
+		{`"\r"`, "\r"},
+		{`"\u{1F9A5} sloth"`, "\U0001F9A5 sloth"},
+		{`"\u{e9}"`, "é"},
+		{`"\u{zz}"`, `\u{zz}`},
+		{`"\u41"`, `\u41`},
+		{`"\q"`, `\q`},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != token.STRING {
+			t.Fatalf("%s - token type wrong. exected=%q, got=%q", tt.input, token.STRING, tok.Type)
+		}
+
+		if tok.Literal != tt.expected {
+			t.Errorf("%s - literal wrong. exected=%q, got=%q", tt.input, tt.expected, tok.Literal)
+		}
+
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Errorf("%s - expected EOF got=%q", tt.input, tok.Type)
+		}
+	}
+}
+
+func TestUnterminatedString(t *testing.T) {
+	tests := []string{
+		`"never closed`,
+		`"ends with escape\`,
+		`"escaped quote\"`,
+	}
+
+	for _, input := range tests {
+		l := New(input)
+		tok := l.NextToken()
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("%s - token type wrong. exected=%q, got=%q", input, token.ILLEGAL, tok.Type)
+		}
+
+		if tok.Literal != input {
+			t.Errorf("%s - literal wrong. exected=%q, got=%q", input, input, tok.Literal)
+		}
+
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Errorf("%s - expected EOF got=%q", input, tok.Type)
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	input := `"total: ${a + b}!" "${x}${ {"k": "${y}"}["k"] }" "cost \${x}"`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		exceptedLiteral string
+	}{
+		{token.INTERP_HEAD, "total: "},
+		{token.IDENT, "a"},
+		{token.PLUS, "+"},
+		{token.IDENT, "b"},
+		{token.INTERP_TAIL, "!"},
+
+		{token.INTERP_HEAD, ""},
+		{token.IDENT, "x"},
+		{token.INTERP_MIDDLE, ""},
+		{token.LBRACE, "{"},
+		{token.STRING, "k"},
+		{token.COLON, ":"},
+		{token.INTERP_HEAD, ""},
+		{token.IDENT, "y"},
+		{token.INTERP_TAIL, ""},
+		{token.RBRACE, "}"},
+		{token.LBRACKET, "["},
+		{token.STRING, "k"},
+		{token.RBRACKET, "]"},
+		{token.INTERP_TAIL, ""},
+
+		{token.STRING, "cost ${x}"},
 		{token.EOF, ""},
 	}
 
@@ -146,3 +418,77 @@ func TestNextToken(t *testing.T) {
 	}
 }
 
+func TestRawString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"`no \\n ${escapes} \"here\"`", `no \n ${escapes} "here"`},
+		{"`two\n  lines`", "two\n  lines"},
+		{"`\n    SELECT *\n      FROM t\n\n    WHERE x\n    `", "SELECT *\n  FROM t\n\nWHERE x"},
+		{"`\n\t{\n\t  \"a\": 1\n\t}`", "{\n  \"a\": 1\n}"},
+		{"``", ""},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != token.STRING {
+			t.Fatalf("%q - token type wrong. exected=%q, got=%q", tt.input, token.STRING, tok.Type)
+		}
+
+		if tok.Literal != tt.expected {
+			t.Errorf("%q - literal wrong. exected=%q, got=%q", tt.input, tt.expected, tok.Literal)
+		}
+
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Errorf("%q - expected EOF got=%q", tt.input, tok.Type)
+		}
+	}
+
+	l := New("`never closed")
+	if tok := l.NextToken(); tok.Type != token.ILLEGAL || tok.Literal != "`never closed" {
+		t.Errorf("unterminated raw string - expected ILLEGAL got=%q (%q)", tok.Type, tok.Literal)
+	}
+}
+
+func TestTokenLines(t *testing.T) {
+	input := `var x = 1;
+// comment
+/* block
+   comment */ const s = "a
+b"; x`
+
+	tests := []struct {
+		expectedType token.TokenType
+		expectedLine int
+	}{
+		{token.VAR, 1},
+		{token.IDENT, 1},
+		{token.ASSIGN, 1},
+		{token.INT, 1},
+		{token.SEMICOLN, 1},
+		{token.CONST, 4},
+		{token.IDENT, 4},
+		{token.ASSIGN, 4},
+		{token.STRING, 4},
+		{token.SEMICOLN, 5},
+		{token.IDENT, 5},
+		{token.EOF, 5},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("test[%d] - token type wrong. exected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Line != tt.expectedLine {
+			t.Fatalf("test[%d] - line wrong. exected=%d, got=%d", i, tt.expectedLine, tok.Line)
+		}
+	}
+}
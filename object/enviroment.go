@@ -1,5 +1,7 @@
 package object
 
+import "fmt"
+
 func NewEnviroment() *Enviroment {
 	s := make(map[string]Object)
 	return &Enviroment{store: s, outer: nil}
@@ -14,6 +16,27 @@ func NewEnclosedEnviroment(outer *Enviroment) *Enviroment {
 type Enviroment struct {
 	store map[string]Object
 	outer *Enviroment
+
+	// line each constant of this scope was declared on
+	constants map[string]int
+
+	// directory of the module this scope belongs to, set on a module's
+	// top-level scope only
+	dir string
+}
+
+// Dir returns the directory of the module the scope belongs to, or "" for
+// code outside any module.
+func (e *Enviroment) Dir() string {
+	if e.dir == "" && e.outer != nil {
+		return e.outer.Dir()
+	}
+	return e.dir
+}
+
+// SetDir records the directory of the module the scope belongs to.
+func (e *Enviroment) SetDir(dir string) {
+	e.dir = dir
 }
 
 func (e *Enviroment) Get(name string) (Object, bool) {
@@ -25,7 +48,40 @@ func (e *Enviroment) Get(name string) (Object, bool) {
 	return obj, ok
 }
 
+// Set binds name in this scope. It returns an error instead when name is
+// already a constant of this scope.
 func (e *Enviroment) Set(name string, val Object) Object {
+	if line, ok := e.constants[name]; ok {
+		return &Error{Message: fmt.Sprintf("cannot redeclare constant %s (declared on line %d)", name, line)}
+	}
 	e.store[name] = val
 	return val
+}
+
+// SetConst binds name in this scope as a constant declared on line.
+func (e *Enviroment) SetConst(name string, val Object, line int) Object {
+	if res, ok := e.Set(name, val).(*Error); ok {
+		return res
+	}
+	if e.constants == nil {
+		e.constants = make(map[string]int)
+	}
+	e.constants[name] = line
+	return val
+}
+
+// Assign updates an existing binding in the scope that defines it. It
+// reports false when name is not declared anywhere in the chain, and
+// returns an error when the binding is a constant.
+func (e *Enviroment) Assign(name string, val Object) (Object, bool) {
+	if line, ok := e.constants[name]; ok {
+		return &Error{Message: fmt.Sprintf("cannot reassign constant %s (declared on line %d)", name, line)}, true
+	}
+	if _, ok := e.store[name]; ok {
+		return e.Set(name, val), true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return nil, false
 }
\ No newline at end of file
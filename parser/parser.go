@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/nazeemnato/sloth/ast"
 	"github.com/nazeemnato/sloth/lexer"
@@ -12,26 +13,38 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN
+	LOGICAL_OR
+	LOGICAL_AND
 	EQUALS
 	LESSGREATER
 	SUM
 	PRODUCT
 	PREFIX
+	POWER
 	CALL
 	INDEX
 )
 
 var procedences = map[token.TokenType]int{
+	token.ASSIGN:   ASSIGN,
+	token.OR:       LOGICAL_OR,
+	token.AND:      LOGICAL_AND,
 	token.EQ:       EQUALS,
 	token.NOT_EQ:   EQUALS,
 	token.LT:       LESSGREATER,
 	token.GT:       LESSGREATER,
+	token.LT_EQ:    LESSGREATER,
+	token.GT_EQ:    LESSGREATER,
 	token.PLUS:     SUM,
 	token.MINUS:    SUM,
 	token.SLASH:    PRODUCT,
 	token.ASTERISK: PRODUCT,
+	token.PERCENT:  PRODUCT,
+	token.POWER:    POWER,
 	token.LPAREN:   CALL,
 	token.LBRACKET: INDEX,
+	token.DOT:      INDEX,
 }
 
 type Parser struct {
@@ -40,6 +53,13 @@ type Parser struct {
 	peekToken token.Token
 	errors    []string
 
+	// number of enclosing blocks, zero at the top level
+	blocks int
+
+	// set while parsing the top level of match patterns and guards, which
+	// are followed by => themselves
+	noArrow bool
+
 	prefixParseFns map[token.TokenType]prefixParseFn
 	inflixParseFns map[token.TokenType]inflixParseFn
 }
@@ -48,17 +68,24 @@ func New(l *lexer.Lexer) *Parser {
 	p := &Parser{l: l, errors: []string{}}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
-	p.registerPrefix(token.IDENT, p.parseIdentifier)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
+	p.registerPrefix(token.IDENT, p.parseIdentifierOrArrow)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNullLiteral)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.INTERP_HEAD, p.parseInterpolatedString)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
 	
 	p.inflixParseFns = make(map[token.TokenType]inflixParseFn)
 	p.registerInflix(token.PLUS, p.parseInflixExpression)
@@ -69,8 +96,16 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInflix(token.NOT_EQ, p.parseInflixExpression)
 	p.registerInflix(token.LT, p.parseInflixExpression)
 	p.registerInflix(token.GT, p.parseInflixExpression)
+	p.registerInflix(token.LT_EQ, p.parseInflixExpression)
+	p.registerInflix(token.GT_EQ, p.parseInflixExpression)
+	p.registerInflix(token.PERCENT, p.parseInflixExpression)
+	p.registerInflix(token.POWER, p.parseInflixExpression)
+	p.registerInflix(token.AND, p.parseInflixExpression)
+	p.registerInflix(token.OR, p.parseInflixExpression)
 	p.registerInflix(token.LPAREN, p.parseCallExpression)
 	p.registerInflix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInflix(token.DOT, p.parseMemberExpression)
+	p.registerInflix(token.ASSIGN, p.parseAssignExpression)
 
 	p.nextToken()
 	p.nextToken()
@@ -103,10 +138,26 @@ func (p *Parser) ParseProgram() *ast.Program {
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
-	case token.VAR:
+	case token.VAR, token.CONST:
 		return p.parseVarStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.WHILE:
+		return p.parseWhileStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -116,14 +167,45 @@ func (p *Parser) parseIdentifier() ast.Expression {
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+// parseIdentifierOrArrow parses an identifier, or the single parameter
+// arrow function `x => ...` when one follows.
+func (p *Parser) parseIdentifierOrArrow() ast.Expression {
+	if p.noArrow || !p.peekTokenIs(token.ARROW) {
+		return p.parseIdentifier()
+	}
+
+	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	p.nextToken()
+
+	lit := &ast.FunctionLiteral{
+		Token:      p.curToken,
+		Parameters: []*ast.Identifier{ident},
+		Defaults:   map[string]ast.Expression{},
+	}
+	lit.Body = p.parseArrowBody()
+	if lit.Body == nil {
+		return nil
+	}
+
+	return lit
+}
+
 func (p *Parser) parseVarStatement() *ast.VarStatement {
 	stmt := &ast.VarStatement{Token: p.curToken}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
+	if p.peekTokenIs(token.LBRACKET) {
+		p.nextToken()
+		stmt.Pattern = p.parseDestructuringPattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
@@ -138,8 +220,61 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 	return stmt
 }
 
+// parseDestructuringPattern parses the array pattern of a var statement.
+// Unlike match patterns it may only hold names and nested array patterns.
+func (p *Parser) parseDestructuringPattern() *ast.ArrayPattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		switch p.curToken.Type {
+		case token.ELLIPSIS:
+			pattern.Rest = p.parsePatternRest()
+			if pattern.Rest == nil {
+				return nil
+			}
+		case token.IDENT:
+			pattern.Elements = append(pattern.Elements, p.parseIdentifier())
+		case token.LBRACKET:
+			element := p.parseDestructuringPattern()
+			if element == nil {
+				return nil
+			}
+			pattern.Elements = append(pattern.Elements, element)
+		default:
+			msg := fmt.Sprintf("cannot destructure into %s", p.curToken.Literal)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		if pattern.Rest != nil {
+			break
+		}
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
+
+	// a bare return gives back null
+	if p.peekTokenIs(token.SEMICOLN) || p.peekTokenIs(token.RBRACE) || p.peekTokenIs(token.EOF) {
+		if p.peekTokenIs(token.SEMICOLN) {
+			p.nextToken()
+		}
+		return stmt
+	}
+
 	p.nextToken()
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
@@ -150,6 +285,269 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLN) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+
+	stmt.Path = p.curToken.Literal
+
+	if !p.expectPeek(token.AS) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.SEMICOLN) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseExportStatement() *ast.ExportStatement {
+	stmt := &ast.ExportStatement{Token: p.curToken}
+
+	if p.blocks > 0 {
+		p.errors = append(p.errors, "export is only allowed at the top level")
+		return nil
+	}
+
+	p.nextToken()
+
+	switch p.curToken.Type {
+	case token.VAR, token.CONST:
+		decl := p.parseVarStatement()
+		if decl == nil {
+			return nil
+		}
+		stmt.Statement = decl
+	case token.STRUCT:
+		decl := p.parseStructStatement()
+		if decl == nil {
+			return nil
+		}
+		stmt.Statement = decl
+	default:
+		msg := fmt.Sprintf("expected var, const or struct after export, got %s instead", p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	return stmt
+}
+
+// parseStructStatement parses a struct declaration. Its body lists fields,
+// optionally with a default value, and `fun name() { }` methods, separated
+// by commas, semicolons or nothing at all.
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	stmt := &ast.StructStatement{Token: p.curToken, Defaults: map[string]ast.Expression{}}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	seen := map[string]bool{}
+
+	for !p.peekTokenIs(token.RBRACE) && !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+
+		var name *ast.Identifier
+
+		switch p.curToken.Type {
+		case token.COMMA, token.SEMICOLN:
+			continue
+		case token.FUNCTION:
+			method := p.parseStructMethod()
+			if method == nil {
+				return nil
+			}
+			name = method.Name
+			stmt.Methods = append(stmt.Methods, method)
+		case token.IDENT:
+			name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			stmt.Fields = append(stmt.Fields, name)
+
+			if p.peekTokenIs(token.ASSIGN) {
+				p.nextToken()
+				p.nextToken()
+				stmt.Defaults[name.Value] = p.parseExpression(LOWEST)
+			}
+		default:
+			msg := fmt.Sprintf("expected field or method in struct %s, got %s instead", stmt.Name.Value, p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		if seen[name.Value] {
+			msg := fmt.Sprintf("duplicate member %s in struct %s", name.Value, stmt.Name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		seen[name.Value] = true
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLN) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseStructMethod() *ast.StructMethod {
+	lit := &ast.FunctionLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	method := &ast.StructMethod{
+		Name:     &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+		Function: lit,
+	}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if !p.parseFunctionParameters(lit) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body = p.parseBlockStatement()
+
+	return method
+}
+
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	stmt := &ast.WhileStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.SEMICOLN) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseForStatement() *ast.ForStatement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Index = stmt.Value
+		stmt.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.SEMICOLN) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.SEMICOLN) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.SEMICOLN) {
+		p.nextToken()
+	}
+	return stmt
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 
@@ -193,6 +591,18 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	lit.Value = value
+	return lit
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.curToken.Type == t
 }
@@ -233,6 +643,24 @@ func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 	p.errors = append(p.errors, msg)
 }
 
+func (p *Parser) parseIllegal() ast.Expression {
+	literal := p.curToken.Literal
+
+	var msg string
+	switch {
+	case strings.HasPrefix(literal, "/*"):
+		msg = "unterminated block comment"
+	case strings.HasPrefix(literal, `"`), strings.HasPrefix(literal, "}"):
+		msg = "unterminated string literal"
+	case strings.HasPrefix(literal, "`"):
+		msg = "unterminated raw string literal"
+	default:
+		msg = fmt.Sprintf("illegal character %q", literal)
+	}
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{
 		Token:    p.curToken,
@@ -266,16 +694,53 @@ func (p *Parser) parseInflixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.curPrecedence()
+	if p.curTokenIs(token.POWER) {
+		// ** is right associative: 2 ** 3 ** 2 is 2 ** (3 ** 2)
+		precedence--
+	}
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
 	return expression
 }
 
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	// a target that failed to parse has reported its own error already
+	if target == nil {
+		return nil
+	}
+
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression, *ast.MemberExpression:
+	default:
+		// the target may be half parsed, so don't print the whole of it
+		msg := fmt.Sprintf("invalid assignment target %s", target.TokenLiteral())
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	expression := &ast.AssignExpression{Token: p.curToken, Target: target}
+
+	p.nextToken()
+	// one below ASSIGN so that a = b = c groups as a = (b = c)
+	expression.Value = p.parseExpression(ASSIGN - 1)
+	return expression
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
 
+func (p *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	if p.isArrowParameters() {
+		return p.parseArrowFunction()
+	}
+
+	defer p.allowArrows(true)()
+
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
@@ -310,6 +775,16 @@ func (p *Parser) parseIfExpression() ast.Expression {
 
 	if p.peekTokenIs(token.ELSE) {
 		p.nextToken()
+
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			expression.Alternative = p.parseElseIf()
+			if expression.Alternative == nil {
+				return nil
+			}
+			return expression
+		}
+
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
@@ -318,10 +793,29 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+// parseElseIf wraps the if expression of an `else if` in a block so it can
+// be used as the Alternative of the preceding if.
+func (p *Parser) parseElseIf() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken}
+
+	stmt := &ast.ExpressionStatement{Token: p.curToken}
+	stmt.Expression = p.parseIfExpression()
+	if stmt.Expression == nil {
+		return nil
+	}
+
+	block.Statements = []ast.Statement{stmt}
+	return block
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
 
+	p.blocks++
+	defer func() { p.blocks-- }()
+	defer p.allowArrows(true)()
+
 	p.nextToken()
 
 	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
@@ -333,6 +827,57 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	return block
 }
 
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		expression.Param = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Handler = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Finally = p.parseBlockStatement()
+	}
+
+	if expression.Handler == nil && expression.Finally == nil {
+		p.errors = append(p.errors, "expected catch or finally after try block")
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	lit := &ast.FunctionLiteral{Token: p.curToken}
 
@@ -340,7 +885,9 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	if !p.parseFunctionParameters(lit) {
+		return nil
+	}
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
@@ -351,32 +898,121 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return lit
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifier := []*ast.Identifier{}
+// allowArrows turns arrow functions on or off and returns a function that
+// restores the previous setting. Brackets turn them back on, since only a
+// => at the top level of a match pattern or guard ends it.
+func (p *Parser) allowArrows(allow bool) (restore func()) {
+	prev := p.noArrow
+	p.noArrow = !allow
+	return func() { p.noArrow = prev }
+}
 
-	if p.peekTokenIs(token.RPAREN) {
+// isArrowParameters reports whether the ( at curToken opens the parameter
+// list of an arrow function, that is whether its matching ) is followed by =>.
+func (p *Parser) isArrowParameters() bool {
+	if p.noArrow {
+		return false
+	}
+
+	l := p.l.Clone()
+	depth := 1
+
+	for tok := p.peekToken; tok.Type != token.EOF; tok = l.NextToken() {
+		switch tok.Type {
+		case token.LPAREN:
+			depth++
+		case token.RPAREN:
+			depth--
+			if depth == 0 {
+				return l.NextToken().Type == token.ARROW
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *Parser) parseArrowFunction() ast.Expression {
+	lit := &ast.FunctionLiteral{}
+
+	if !p.parseFunctionParameters(lit) {
+		return nil
+	}
+
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+	lit.Token = p.curToken
+
+	lit.Body = p.parseArrowBody()
+	if lit.Body == nil {
+		return nil
+	}
+
+	return lit
+}
+
+// parseArrowBody parses what follows =>, either a block or a single
+// expression whose value is returned.
+func (p *Parser) parseArrowBody() *ast.BlockStatement {
+	if p.peekTokenIs(token.LBRACE) {
 		p.nextToken()
-		return identifier
+		return p.parseBlockStatement()
 	}
 
 	p.nextToken()
+	stmt := &ast.ExpressionStatement{Token: p.curToken}
+	stmt.Expression = p.parseExpression(LOWEST)
+	if stmt.Expression == nil {
+		return nil
+	}
 
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifier = append(identifier, ident)
+	return &ast.BlockStatement{Token: stmt.Token, Statements: []ast.Statement{stmt}}
+}
 
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken()
+// parseFunctionParameters fills in the parameters of lit, including
+// default values and a trailing ...rest parameter.
+func (p *Parser) parseFunctionParameters(lit *ast.FunctionLiteral) bool {
+	lit.Parameters = []*ast.Identifier{}
+	lit.Defaults = map[string]ast.Expression{}
+
+	for !p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
 
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return false
+			}
+			lit.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+			if !p.peekTokenIs(token.RPAREN) {
+				p.errors = append(p.errors, "rest parameter must be last")
+				return false
+			}
+			break
+		}
+
+		if !p.curTokenIs(token.IDENT) {
+			msg := fmt.Sprintf("expected parameter name got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return false
+		}
+
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifier = append(identifier, ident)
-	}
+		lit.Parameters = append(lit.Parameters, ident)
 
-	if !p.expectPeek(token.RPAREN) {
-		return nil
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			lit.Defaults[ident.Value] = p.parseExpression(LOWEST)
+		}
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return false
+		}
 	}
 
-	return identifier
+	return p.expectPeek(token.RPAREN)
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
@@ -413,6 +1049,50 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.curToken}
+	str.Parts = p.appendStringPart(str.Parts)
+
+	for {
+		if p.peekTokenIs(token.INTERP_MIDDLE) || p.peekTokenIs(token.INTERP_TAIL) {
+			p.errors = append(p.errors, "empty expression in string interpolation")
+			return nil
+		}
+
+		p.nextToken()
+		str.Parts = append(str.Parts, p.parseExpression(LOWEST))
+		p.nextToken()
+
+		switch p.curToken.Type {
+		case token.INTERP_MIDDLE:
+			str.Parts = p.appendStringPart(str.Parts)
+		case token.INTERP_TAIL:
+			str.Parts = p.appendStringPart(str.Parts)
+			return str
+		case token.ILLEGAL:
+			p.parseIllegal()
+			return nil
+		case token.EOF:
+			p.errors = append(p.errors, "unterminated string interpolation")
+			return nil
+		default:
+			msg := fmt.Sprintf("expected } to close string interpolation got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+	}
+}
+
+// appendStringPart adds the text of the current string piece to parts,
+// leaving out empty text between interpolations.
+func (p *Parser) appendStringPart(parts []ast.Expression) []ast.Expression {
+	if p.curToken.Literal == "" {
+		return parts
+	}
+	text := &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+	return append(parts, text)
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 	array.Elements = p.parseExpressionList(token.RBRACKET)
@@ -422,6 +1102,8 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	list := []ast.Expression{}
 
+	defer p.allowArrows(true)()
+
 	if p.peekTokenIs(end) {
 		p.nextToken()
 		return list
@@ -443,11 +1125,33 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	return list
 }
 
+// parseIndexExpression parses left[index] as well as the slices
+// left[start:end], left[start:] and left[:end].
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	tok := p.curToken
+	var index ast.Expression
+
+	defer p.allowArrows(true)()
+
+	if !p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		index = p.parseExpression(LOWEST)
+	}
+
+	if !p.peekTokenIs(token.COLON) {
+		if !p.expectPeek(token.RBRACKET) {
+			return nil
+		}
+		return &ast.IndexExpression{Token: tok, Left: left, Index: index}
+	}
+
 	p.nextToken()
+	exp := &ast.SliceExpression{Token: tok, Left: left, Start: index}
 
-	exp.Index = p.parseExpression(LOWEST)
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.End = p.parseExpression(LOWEST)
+	}
 
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
@@ -455,3 +1159,164 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 
 	return exp
 }
+
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: left}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken}
+	hash.Pairs = make(map[ast.Expression]ast.Expression)
+
+	defer p.allowArrows(true)()
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		hash.Keys = append(hash.Keys, key)
+		hash.Pairs[key] = value
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return hash
+}
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.curToken}
+
+	defer p.allowArrows(true)()
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		arm := &ast.MatchArm{Pattern: p.parsePattern()}
+		if arm.Pattern == nil {
+			return nil
+		}
+
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			p.nextToken()
+			restore := p.allowArrows(false)
+			arm.Guard = p.parseExpression(LOWEST)
+			restore()
+		}
+
+		if !p.expectPeek(token.ARROW) {
+			return nil
+		}
+
+		p.nextToken()
+		arm.Body = p.parseExpression(LOWEST)
+		expression.Arms = append(expression.Arms, arm)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return expression
+}
+
+// parsePattern parses a match pattern. Identifiers bind the matched value
+// (`_` matches without binding), brackets start an array pattern and
+// anything else is an expression the value has to be equal to.
+func (p *Parser) parsePattern() ast.Expression {
+	switch p.curToken.Type {
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.IDENT:
+		return p.parseIdentifier()
+	default:
+		defer p.allowArrows(false)()
+		return p.parseExpression(LOWEST)
+	}
+}
+
+func (p *Parser) parseArrayPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			pattern.Rest = p.parsePatternRest()
+			if pattern.Rest == nil {
+				return nil
+			}
+			break
+		}
+
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+// parsePatternRest parses the `...name` that ends an array pattern.
+func (p *Parser) parsePatternRest() *ast.Identifier {
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	rest := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.errors = append(p.errors, "rest element must be last in array pattern")
+		return nil
+	}
+	return rest
+}
@@ -2,11 +2,13 @@ package parser_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/nazeemnato/sloth/ast"
 	"github.com/nazeemnato/sloth/lexer"
 	"github.com/nazeemnato/sloth/parser"
+	"github.com/nazeemnato/sloth/token"
 )
 
 func TestVarStatement(t *testing.T) {
@@ -50,6 +52,77 @@ func TestVarStatement(t *testing.T) {
 	}
 }
 
+func TestVarStatementDestructuring(t *testing.T) {
+	input := `var [a, [b, _], ...rest] = xs;`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.VarStatement)
+	if !ok {
+		t.Fatalf("stmt not *ast.VarStatement got=%T", program.Statements[0])
+	}
+
+	if stmt.Name != nil {
+		t.Errorf("stmt.Name is not nil got=%+v", stmt.Name)
+	}
+
+	if stmt.Pattern == nil {
+		t.Fatalf("stmt.Pattern is nil")
+	}
+
+	if len(stmt.Pattern.Elements) != 2 {
+		t.Fatalf("wrong number of pattern elements. expected=2, got=%d", len(stmt.Pattern.Elements))
+	}
+
+	testIdentifer(t, stmt.Pattern.Elements[0], "a")
+	if _, ok := stmt.Pattern.Elements[1].(*ast.ArrayPattern); !ok {
+		t.Errorf("Elements[1] is not ast.ArrayPattern got=%T", stmt.Pattern.Elements[1])
+	}
+	testIdentifer(t, stmt.Pattern.Rest, "rest")
+	testIdentifer(t, stmt.Value, "xs")
+
+	if stmt.String() != "var [a, [b, _], ...rest] = xs;" {
+		t.Errorf("stmt.String() wrong got=%q", stmt.String())
+	}
+}
+
+func TestConstStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const x = 5;", "const x = 5;"},
+		{"const [a, b] = pair", "const [a, b] = pair;"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.VarStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.VarStatement got=%T", program.Statements[0])
+		}
+		if stmt.Token.Type != token.CONST {
+			t.Errorf("stmt.Token.Type is not CONST got=%q", stmt.Token.Type)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
 func TestReturnStatement(t *testing.T) {
 	inputr := `return 5;
 	return 10;
@@ -153,6 +226,36 @@ func TestIntegerLiteralExpress(t *testing.T) {
 	}
 }
 
+func TestFloatLiteralExpression(t *testing.T) {
+	input := `3.25;`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Program.statement[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.FloatLiteral)
+	if !ok {
+		t.Fatalf("Exp no *ast.FloatLiteral got=%T", stmt.Expression)
+	}
+	if literal.Value != 3.25 {
+		t.Errorf("literal value not 3.25 got=%f", literal.Value)
+	}
+	if literal.TokenLiteral() != "3.25" {
+		t.Errorf("literal token literal not 3.25 got=%s", literal.TokenLiteral())
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTest := []struct {
 		input        string
@@ -213,6 +316,12 @@ func TestParsingInflixExpression(t *testing.T) {
 		{"true == true", true, "==", true},
 		{"false == true", false, "==", true},
 		{"false != true", false, "!=", true},
+		{"true && false", true, "&&", false},
+		{"true || false", true, "||", false},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
 	}
 
 	for _, tt := range inflixTests {
@@ -264,6 +373,16 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"false", "false"},
 		{"3 > 5 == false", "((3 > 5) == false)"},
 		{"a * [1,2,3,5]", "((a * [1, 2, 3, 5]))"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c", "((a && b) || c)"},
+		{"a == b && c < d", "((a == b) && (c < d))"},
+		{"x = a || b", "(x = (a || b))"},
+		{"a <= b == c >= d", "((a <= b) == (c >= d))"},
+		{"a + b % c", "(a + (b % c))"},
+		{"a * b ** c", "(a * (b ** c))"},
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"-a ** b", "(-(a ** b))"},
+		{"a ** -b", "(a ** (-b))"},
 	}
 
 	for _, tt := range tests {
@@ -316,6 +435,43 @@ func TestBooleanExpression(t *testing.T) {
 	}
 }
 
+func TestNullLiteral(t *testing.T) {
+	l := lexer.New("null;")
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	null, ok := stmt.Expression.(*ast.NullLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.NullLiteral got=%T", stmt.Expression)
+	}
+	if null.TokenLiteral() != "null" {
+		t.Errorf("null.TokenLiteral not null got=%s", null.TokenLiteral())
+	}
+}
+
+func TestBareReturnStatement(t *testing.T) {
+	tests := []string{"return;", "return", "fun() { return }"}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("%s - Program statements does not contain 1 statments. got=%d", input, len(program.Statements))
+		}
+	}
+}
+
 func TestIfExpression(t *testing.T) {
 	input := `if (x < y) { x } else { y }`
 
@@ -363,6 +519,53 @@ func TestIfExpression(t *testing.T) {
 	}
 }
 
+func TestElseIfExpression(t *testing.T) {
+	input := `if (x < y) { x } else if (x > y) { y } else { z }`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression, got=%T", stmt.Expression)
+	}
+
+	elseIf := exp.ElseIf()
+	if elseIf == nil {
+		t.Fatalf("exp.ElseIf() is nil, Alternative=%+v", exp.Alternative)
+	}
+
+	if !testInflixExpression(t, elseIf.Condition, "x", ">", "y") {
+		return
+	}
+
+	if elseIf.Alternative == nil || elseIf.ElseIf() != nil {
+		t.Fatalf("last branch is not a plain else got=%+v", elseIf.Alternative)
+	}
+
+	expected := "if ((x < y)) { x } else if ((x > y)) { y } else { z }"
+	if program.String() != expected {
+		t.Fatalf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+
+	// the printed form parses back to the same program
+	p = parser.New(lexer.New(program.String()))
+	reparsed := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if reparsed.String() != expected {
+		t.Errorf("round trip wrong. expected=%q, got=%q", expected, reparsed.String())
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := `fun(x, y) { x + y; }`
 
@@ -436,6 +639,62 @@ func TestFunctionLiteralParameterParsing(t *testing.T) {
 	}
 }
 
+func TestDefaultAndRestParameterParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fun(a, b = 2) { a }", "fun(a, b = 2) a"},
+		{"fun(a, ...rest) { a }", "fun(a, ...rest) a"},
+		{"fun(a = 1 + 2, ...rest) { a }", "fun(a = (1 + 2), ...rest) a"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestArrowFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(x) => x * 2", "(x) => (x * 2)"},
+		{"x => x * 2", "(x) => (x * 2)"},
+		{"() => 1", "() => 1"},
+		{"(a, b = 1, ...rest) => { a + b }", "(a, b = 1, ...rest) => (a + b)"},
+		{"map(xs, (x) => x + 1)", "map(xs, (x) => (x + 1))"},
+		{"(x) => (y) => x + y", "(x) => (y) => (x + y)"},
+		{"(a + b) * c", "((a + b) * c)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := parser.New(lexer.New("(x) => x")).ParseProgram()
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral got=%T", stmt.Expression)
+	}
+	testLiteralExpression(t, function.Parameters[0], "x")
+}
+
 func TestCallExpression(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5);"
 
@@ -676,3 +935,604 @@ func TestParsingIndexExpressions(t *testing.T) {
 		return
 	}
 }
+
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"xs[1:2]", "(xs[1:2])"},
+		{"xs[1:]", "(xs[1:])"},
+		{"xs[:2]", "(xs[:2])"},
+		{"xs[:]", "(xs[:])"},
+		{"xs[a + 1:len(xs) - 1]", "(xs[(a + 1):(len(xs) - 1)])"},
+		{"xs[1:][0]", "((xs[1:])[0])"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := parser.New(lexer.New("xs[:2]")).ParseProgram()
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	slice, ok := stmt.Expression.(*ast.SliceExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.SliceExpression. got=%T", stmt.Expression)
+	}
+	if slice.Start != nil {
+		t.Errorf("slice.Start is not nil. got=%+v", slice.Start)
+	}
+	testLiteralExpression(t, slice.End, 2)
+}
+
+func TestParsingHashLiteralsStringKeys(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3}`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 3 {
+		t.Errorf("hash.Pairs has wrong length got=%d", len(hash.Pairs))
+	}
+
+	expected := map[string]int64{
+		"one":   1,
+		"two":   2,
+		"three": 3,
+	}
+
+	for key, value := range hash.Pairs {
+		literal, ok := key.(*ast.StringLiteral)
+		if !ok {
+			t.Errorf("key is not ast.StringLiteral got=%T", key)
+		}
+
+		expectedValue := expected[literal.String()]
+		testIntegerLiteral(t, value, expectedValue)
+	}
+
+	if hash.String() != `{one: 1, two: 2, three: 3}` {
+		t.Errorf("hash.String() wrong got=%q", hash.String())
+	}
+}
+
+func TestParsingEmptyHashLiteral(t *testing.T) {
+	input := "{}"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 0 {
+		t.Errorf("hash.Pairs has wrong length got=%d", len(hash.Pairs))
+	}
+}
+
+func TestParsingHashLiteralsWithExpressions(t *testing.T) {
+	input := `{"one": 0 + 1, "two": 10 - 8, "three": 15 / 5}`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 3 {
+		t.Errorf("hash.Pairs has wrong length got=%d", len(hash.Pairs))
+	}
+
+	tests := map[string]func(ast.Expression){
+		"one": func(e ast.Expression) {
+			testInflixExpression(t, e, 0, "+", 1)
+		},
+		"two": func(e ast.Expression) {
+			testInflixExpression(t, e, 10, "-", 8)
+		},
+		"three": func(e ast.Expression) {
+			testInflixExpression(t, e, 15, "/", 5)
+		},
+	}
+
+	for key, value := range hash.Pairs {
+		literal, ok := key.(*ast.StringLiteral)
+		if !ok {
+			t.Errorf("key is not ast.StringLiteral got=%T", key)
+			continue
+		}
+
+		testFunc, ok := tests[literal.String()]
+		if !ok {
+			t.Errorf("No test function for key %q found", literal.String())
+			continue
+		}
+
+		testFunc(value)
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	input := `while (x < 10) { x; break; continue; };`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("Program.statement[0] is not ast.WhileStatement. got=%T", program.Statements[0])
+	}
+
+	if !testInflixExpression(t, stmt.Condition, "x", "<", 10) {
+		return
+	}
+
+	if len(stmt.Body.Statements) != 3 {
+		t.Fatalf("Body is not 3 statements got=%d", len(stmt.Body.Statements))
+	}
+
+	if _, ok := stmt.Body.Statements[1].(*ast.BreakStatement); !ok {
+		t.Errorf("Body.Statements[1] is not ast.BreakStatement got=%T", stmt.Body.Statements[1])
+	}
+
+	if _, ok := stmt.Body.Statements[2].(*ast.ContinueStatement); !ok {
+		t.Errorf("Body.Statements[2] is not ast.ContinueStatement got=%T", stmt.Body.Statements[2])
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedIndex string
+		expectedValue string
+	}{
+		{"for (x in xs) { x }", "", "x"},
+		{"for (i, x in xs) { x }", "i", "x"},
+		{"for (x in xs) { x };", "", "x"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ForStatement)
+		if !ok {
+			t.Fatalf("Program.statement[0] is not ast.ForStatement. got=%T", program.Statements[0])
+		}
+
+		if tt.expectedIndex == "" {
+			if stmt.Index != nil {
+				t.Errorf("stmt.Index was not nil got=%+v", stmt.Index)
+			}
+		} else if !testIdentifer(t, stmt.Index, tt.expectedIndex) {
+			return
+		}
+
+		if !testIdentifer(t, stmt.Value, tt.expectedValue) {
+			return
+		}
+
+		if !testIdentifer(t, stmt.Iterable, "xs") {
+			return
+		}
+
+		if len(stmt.Body.Statements) != 1 {
+			t.Errorf("Body is not 1 statements got=%d", len(stmt.Body.Statements))
+		}
+	}
+}
+
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 5", "(x = 5)"},
+		{"x = y = 5 + 1", "(x = (y = (5 + 1)))"},
+		{"xs[0] = 1", "((xs[0]) = 1)"},
+		{"x = a == b", "(x = (a == b))"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if _, ok := stmt.Expression.(*ast.AssignExpression); !ok {
+			t.Fatalf("exp is not ast.AssignExpression got=%T", stmt.Expression)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestInvalidAssignTarget(t *testing.T) {
+	l := lexer.New("5 = 3")
+	p := parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("parser has %d errors, want 1", len(errors))
+	}
+
+	if errors[0] != "invalid assignment target 5" {
+		t.Errorf("wrong error got=%q", errors[0])
+	}
+}
+
+func TestMalformedAssignTarget(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"a. = 3", "expected next token to be  IDENT got = instead"},
+		{"(1 +) = 2", "No prefix parse function for ) found"},
+		{"if (1 +) = 2 {}", "No prefix parse function for ) found"},
+		{"var [a, !] = 1", "cannot destructure into !"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q", tt.input)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestIllegalTokenErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"1 + /* oops", "unterminated block comment"},
+		{`var s = "oops`, "unterminated string literal"},
+		{`"a ${b} c`, "unterminated string literal"},
+		{`"a ${b`, "unterminated string interpolation"},
+		{`"a ${} c"`, "empty expression in string interpolation"},
+		{"`oops", "unterminated raw string literal"},
+		{"match (x) { [...r, a] => a }", "rest element must be last in array pattern"},
+		{"var [a, 1] = xs", "cannot destructure into 1"},
+		{"var [a, [b, 2]] = xs", "cannot destructure into 2"},
+		{"var [-,] = 1", "cannot destructure into -"},
+		{"var [a = 1] = xs", "expected next token to be  , got = instead"},
+		{"var [a, ...b, c] = xs", "rest element must be last in array pattern"},
+		{"fun(...rest, a) { a }", "rest parameter must be last"},
+		{"fun(1) { 1 }", "expected parameter name got INT instead"},
+		{"((a)) => a", "expected parameter name got ( instead"},
+		{"try { 1 }", "expected catch or finally after try block"},
+		{"struct P { x, x }", "duplicate member x in struct P"},
+		{"struct P { x; fun x() { 1 } }", "duplicate member x in struct P"},
+		{"struct P { 1 }", "expected field or method in struct P, got INT instead"},
+		{"export 1", "expected var, const or struct after export, got INT instead"},
+		{"fun() { export var x = 1 }", "export is only allowed at the top level"},
+		{"import lib as lib", "expected next token to be  STRING got IDENT instead"},
+		{"1 @ 2", `illegal character "@"`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q", tt.input)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestInterpolatedString(t *testing.T) {
+	input := `"total: ${a + b} items"`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	str, ok := stmt.Expression.(*ast.InterpolatedString)
+	if !ok {
+		t.Fatalf("exp not *ast.InterpolatedString got=%T", stmt.Expression)
+	}
+
+	if len(str.Parts) != 3 {
+		t.Fatalf("wrong number of parts. expected=3, got=%d", len(str.Parts))
+	}
+
+	if text, ok := str.Parts[0].(*ast.StringLiteral); !ok || text.Value != "total: " {
+		t.Errorf("Parts[0] is not \"total: \" got=%T (%+v)", str.Parts[0], str.Parts[0])
+	}
+
+	testInflixExpression(t, str.Parts[1], "a", "+", "b")
+
+	if text, ok := str.Parts[2].(*ast.StringLiteral); !ok || text.Value != " items" {
+		t.Errorf("Parts[2] is not \" items\" got=%T (%+v)", str.Parts[2], str.Parts[2])
+	}
+
+	if str.String() != `"total: ${(a + b)} items"` {
+		t.Errorf("str.String() wrong got=%q", str.String())
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { f() } catch (e) { e }", "try { f() } catch (e) { e }"},
+		{"try { f() } finally { g() }", "try { f() } finally { g() }"},
+		{"try { f() } catch (e) { throw e } finally { g() }", "try { f() } catch (e) { throw e; } finally { g() }"},
+		{`throw "oops";`, "throw oops;"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestStructStatement(t *testing.T) {
+	input := `struct Point {
+		x, y = 0
+		fun dist(other) { other.x - self.x }
+	}`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.StructStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.StructStatement got=%T", program.Statements[0])
+	}
+
+	if stmt.Name.Value != "Point" {
+		t.Errorf("stmt.Name.Value not Point got=%q", stmt.Name.Value)
+	}
+
+	if len(stmt.Fields) != 2 {
+		t.Fatalf("wrong number of fields. expected=2, got=%d", len(stmt.Fields))
+	}
+	testLiteralExpression(t, stmt.Fields[0], "x")
+	testLiteralExpression(t, stmt.Fields[1], "y")
+	testLiteralExpression(t, stmt.Defaults["y"], 0)
+
+	if len(stmt.Methods) != 1 {
+		t.Fatalf("wrong number of methods. expected=1, got=%d", len(stmt.Methods))
+	}
+
+	expected := "struct Point { x, y = 0; fun dist(other) ((other.x) - (self.x)) }"
+	if program.String() != expected {
+		t.Errorf("excpected=%q, got=%q", expected, program.String())
+	}
+	p = parser.New(lexer.New("struct P { x }; P(1)"))
+	program = p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("Program statements does not contain 2 statments. got=%d", len(program.Statements))
+	}
+}
+
+func TestMemberExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"p.x", "(p.x)"},
+		{"a.b.c", "((a.b).c)"},
+		{"p.move(1, 2)", "(p.move)(1, 2)"},
+		{"-p.x * 2", "((-(p.x)) * 2)"},
+		{"p.x = p.y + 1", "((p.x) = ((p.y) + 1))"},
+		{"xs[0].name", "((xs[0]).name)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestImportExportStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/math.sloth" as math`, `import "lib/math.sloth" as math;`},
+		{"export var x = 1", "export var x = 1;"},
+		{"export const [a, b] = pair", "export const [a, b] = pair;"},
+		{"export struct P { x }", "export struct P { x }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("excpected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := parser.New(lexer.New("export var [a, _, [b], ...c] = xs")).ParseProgram()
+	stmt, ok := program.Statements[0].(*ast.ExportStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.ExportStatement got=%T", program.Statements[0])
+	}
+	if names := strings.Join(stmt.Names(), ","); names != "a,b,c" {
+		t.Errorf("wrong exported names. got=%q", names)
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	input := `match (x) {
+		0 => "zero",
+		[first, _, ...rest] if first > 1 => rest,
+		n => n,
+	}`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MatchExpression got=%T", stmt.Expression)
+	}
+
+	if !testIdentifer(t, exp.Subject, "x") {
+		return
+	}
+
+	if len(exp.Arms) != 3 {
+		t.Fatalf("wrong number of arms. expected=3, got=%d", len(exp.Arms))
+	}
+
+	testLiteralExpression(t, exp.Arms[0].Pattern, 0)
+	if exp.Arms[0].Guard != nil {
+		t.Errorf("Arms[0].Guard is not nil got=%+v", exp.Arms[0].Guard)
+	}
+
+	pattern, ok := exp.Arms[1].Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("Arms[1].Pattern is not ast.ArrayPattern got=%T", exp.Arms[1].Pattern)
+	}
+	if len(pattern.Elements) != 2 {
+		t.Fatalf("wrong number of pattern elements. expected=2, got=%d", len(pattern.Elements))
+	}
+	testIdentifer(t, pattern.Elements[0], "first")
+	testIdentifer(t, pattern.Elements[1], "_")
+	testIdentifer(t, pattern.Rest, "rest")
+	testInflixExpression(t, exp.Arms[1].Guard, "first", ">", 1)
+	testIdentifer(t, exp.Arms[1].Body, "rest")
+
+	testIdentifer(t, exp.Arms[2].Pattern, "n")
+
+	expected := "match (x) { 0 => zero, [first, _, ...rest] if (first > 1) => rest, n => n }"
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestMatchGuardArrowFunction(t *testing.T) {
+	input := `match (3) { n if [1].map(x => x).len() > 0 => n, _ => 0 }`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("Program statements does not contain 1 statments. got=%d", len(program.Statements))
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MatchExpression got=%T", stmt.Expression)
+	}
+
+	if len(exp.Arms) != 2 {
+		t.Fatalf("wrong number of arms. expected=2, got=%d", len(exp.Arms))
+	}
+
+	guard, ok := exp.Arms[0].Guard.(*ast.InflixExpression)
+	if !ok {
+		t.Fatalf("Arms[0].Guard is not ast.InflixExpression got=%T", exp.Arms[0].Guard)
+	}
+	if guard.Operator != ">" {
+		t.Errorf("guard.Operator is not '>' got=%q", guard.Operator)
+	}
+	testIdentifer(t, exp.Arms[0].Body, "n")
+	testIdentifer(t, exp.Arms[1].Pattern, "_")
+}